@@ -1,17 +1,40 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
+	"task-management/internal/accesstoken"
+	"task-management/internal/account"
+	"task-management/internal/assignment"
 	"task-management/internal/auth"
+	"task-management/internal/comment"
 	"task-management/internal/config"
 	"task-management/internal/database"
+	"task-management/internal/invitation"
+	"task-management/internal/mailer"
+	"task-management/internal/mention"
 	"task-management/internal/middleware"
+	"task-management/internal/notification"
+	"task-management/internal/oidc"
+	"task-management/internal/password"
 	"task-management/internal/project"
+	"task-management/internal/security"
+	"task-management/internal/session"
 	"task-management/internal/subtask"
 	"task-management/internal/task"
+	"task-management/internal/template"
+	"task-management/internal/trash"
+	"task-management/internal/twofactor"
 	"task-management/internal/user"
+	"task-management/internal/usertoken"
+	"task-management/internal/utils"
 
 	"github.com/gin-gonic/gin"
 )
@@ -23,6 +46,20 @@ func main() {
 		log.Fatal(err)
 	}
 
+	// ctx is cancelled on SIGINT/SIGTERM and stops the background loops and the server
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if !project.IsValidRole(config.Project.TransferPreviousOwnerRole) {
+		log.Fatalf("invalid PROJECT_TRANSFER_PREVIOUS_OWNER_ROLE %q", config.Project.TransferPreviousOwnerRole)
+	}
+	if config.Trash.RetentionDays < 1 || config.Trash.PurgeIntervalMinutes < 1 {
+		log.Fatal("TRASH_RETENTION_DAYS and TRASH_PURGE_INTERVAL_MINUTES must be positive")
+	}
+	if config.Notification.DueSoonHours < 1 || config.Notification.DueSoonIntervalMinutes < 1 {
+		log.Fatal("NOTIFICATION_DUE_SOON_HOURS and NOTIFICATION_DUE_SOON_INTERVAL_MINUTES must be positive")
+	}
+
 	postgres, err := database.PostgresConnect(config)
 	if err != nil {
 		log.Fatal(err)
@@ -35,37 +72,139 @@ func main() {
 
 	// db := database.GetDB()
 
+	mail, err := mailer.New(config)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	keys, err := utils.NewKeySet(config)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	hasher, err := password.NewHasher(config)
+	if err != nil {
+		log.Fatal(err)
+	}
+	passwordPolicy := password.NewPolicy(config)
+
 	userRepo := user.NewRepository(postgres)
-	authService := auth.NewAuthService(config, userRepo)
+	authRepo := auth.NewRepository(postgres)
+	userTokenRepo := usertoken.NewRepository(postgres)
+
+	twoFactorRepo := twofactor.NewRepository(postgres)
+	twoFactorService := twofactor.NewService(config, twoFactorRepo, userRepo, hasher, redis)
+	twoFactorController := twofactor.NewController(twoFactorService)
+
+	securityRepo := security.NewRepository(postgres)
+	securityService := security.NewService(config, securityRepo, redis)
+	securityController := security.NewController(securityService)
+
+	sessionRepo := session.NewRepository(postgres)
+	sessionService := session.NewService(config, sessionRepo, redis)
+	sessionController := session.NewController(sessionService)
+
+	authService := auth.NewAuthService(config, keys, userRepo, authRepo, userTokenRepo, mail, hasher, passwordPolicy, twoFactorService, securityService, sessionService, redis)
 	authController := auth.NewAuthController(authService)
 
-	userService := user.NewService(userRepo)
+	oidcRepo := oidc.NewRepository(postgres)
+	oidcService := oidc.NewService(config, oidcRepo, userRepo, authService, redis)
+	oidcController := oidc.NewOIDCController(oidcService)
+
+	accessTokenRepo := accesstoken.NewRepository(postgres)
+	accessTokenService := accesstoken.NewService(accessTokenRepo)
+	accessTokenController := accesstoken.NewController(accessTokenService)
+
+	userService := user.NewService(config, userRepo, userTokenRepo, mail, hasher, passwordPolicy, sessionService)
 	userController := user.NewController(userService)
 
+	accountRepo := account.NewRepository(postgres)
+	accountService := account.NewService(accountRepo, userRepo, hasher, twoFactorService, sessionService, securityService)
+	accountController := account.NewController(accountService)
+
+	notificationRepo := notification.NewRepository(postgres)
+	notificationService := notification.NewService(config, notificationRepo)
+	notificationController := notification.NewController(notificationService)
+	go notification.RunDueSoon(ctx, notificationService, time.Duration(config.Notification.DueSoonIntervalMinutes)*time.Minute)
+
+	mentionRepo := mention.NewRepository(postgres)
+	mentionService := mention.NewService(mentionRepo, notificationService)
+
 	projectRepo := project.NewRepository(postgres)
-	projectService := project.NewService(projectRepo)
+	taskRepo := task.NewRepository(postgres)
+	templateRepo := template.NewRepository(postgres)
+	templateService := template.NewService(templateRepo, projectRepo, taskRepo)
+	templateController := template.NewController(templateService)
+	projectService := project.NewService(config, projectRepo, userRepo, templateService)
 	projectController := project.NewController(projectService)
 
-	taskRepo := task.NewRepository(postgres)
-	taskService := task.NewService(taskRepo, projectRepo)
+	invitationRepo := invitation.NewRepository(postgres)
+	invitationService := invitation.NewService(config, invitationRepo, projectRepo, userRepo, mail)
+	invitationController := invitation.NewController(invitationService)
+
+	taskService := task.NewService(taskRepo, projectRepo, mentionService, notificationService)
 	taskController := task.NewController(taskService)
 
+	assignmentRepo := assignment.NewRepository(postgres)
+	assignmentService := assignment.NewService(assignmentRepo, taskRepo)
+	assignmentController := assignment.NewController(assignmentService)
+
 	subtaskRepo := subtask.NewRepository(postgres)
-	subtaskService := subtask.NewService(subtaskRepo, taskService)
+	subtaskService := subtask.NewService(subtaskRepo, taskService, taskRepo, projectRepo, mentionService, notificationService)
 	subtaskController := subtask.NewController(subtaskService)
 
+	commentRepo := comment.NewRepository(postgres)
+	commentService := comment.NewService(commentRepo, taskRepo, subtaskRepo, projectRepo, mentionService)
+	commentController := comment.NewController(commentService)
+
+	trashRepo := trash.NewRepository(postgres)
+	trashService := trash.NewService(config, trashRepo, projectRepo)
+	trashController := trash.NewController(trashService)
+	go trash.RunPurger(ctx, trashService, time.Duration(config.Trash.PurgeIntervalMinutes)*time.Minute)
+
 	postLoggedIn := middleware.RateLimiterMiddleware(*redis, 1000, 3 * time.Minute)
+	authMw := middleware.AuthMiddleware(keys, redis, accessTokenService, sessionService)
+	noTokenWrites := middleware.RequireScope("")
+	sessionOnly := middleware.RequireSession()
 
 	router := gin.Default()
 	router.Use(middleware.CORSMiddleware(config))
 	router.Use(middleware.TimeoutMiddleware(10 * time.Second))
 	group := router.Group("/")
-	auth.RegisterRoutes(group, authController, middleware.RateLimiterMiddleware(*redis, 10, 60 * time.Second))
+	authRateLimit := middleware.RateLimiterMiddleware(*redis, 10, 60 * time.Second)
+	auth.RegisterRoutes(group, authController, authMw, noTokenWrites, authRateLimit)
+	oidc.RegisterRoutes(group, oidcController, authRateLimit)
 	
-	user.RegisterRoutes(group, userController, middleware.AuthMiddleware(config), postLoggedIn)
-	project.RegisterRoutes(group, projectController, middleware.AuthMiddleware(config), postLoggedIn)
-	task.RegisterRoutes(group, taskController, middleware.AuthMiddleware(config), postLoggedIn)
-	subtask.RegisterRoutes(group, subtaskController, middleware.AuthMiddleware(config), postLoggedIn)
-
-	router.Run(":8080")
+	user.RegisterRoutes(group, userController, authMw, noTokenWrites, postLoggedIn)
+	account.RegisterRoutes(group, accountController, authMw, sessionOnly, postLoggedIn)
+	accesstoken.RegisterRoutes(group, accessTokenController, authMw, sessionOnly, postLoggedIn)
+	twofactor.RegisterRoutes(group, twoFactorController, authMw, sessionOnly, postLoggedIn)
+	security.RegisterRoutes(group, securityController, authMw, sessionOnly, postLoggedIn)
+	session.RegisterRoutes(group, sessionController, authMw, sessionOnly, postLoggedIn)
+	notification.RegisterRoutes(group, notificationController, authMw, noTokenWrites, postLoggedIn)
+	project.RegisterRoutes(group, projectController, authMw, middleware.RequireScope(accesstoken.ScopeProjectsAdmin), postLoggedIn)
+	invitation.RegisterRoutes(group, invitationController, authMw, middleware.RequireScope(accesstoken.ScopeProjectsAdmin), postLoggedIn)
+	template.RegisterRoutes(group, templateController, authMw, middleware.RequireScope(accesstoken.ScopeProjectsAdmin), postLoggedIn)
+	task.RegisterRoutes(group, taskController, authMw, middleware.RequireScope(accesstoken.ScopeTasksWrite), postLoggedIn)
+	subtask.RegisterRoutes(group, subtaskController, authMw, middleware.RequireScope(accesstoken.ScopeTasksWrite), postLoggedIn)
+	comment.RegisterRoutes(group, commentController, authMw, middleware.RequireScope(accesstoken.ScopeTasksWrite), postLoggedIn)
+	assignment.RegisterRoutes(group, assignmentController, authMw, middleware.RequireScope(accesstoken.ScopeTasksWrite), postLoggedIn)
+	trash.RegisterRoutes(group, trashController, authMw, middleware.RequireScope(accesstoken.ScopeProjectsAdmin), postLoggedIn)
+
+	server := &http.Server{Addr: ":8080", Handler: router}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	}()
+
+	<-ctx.Done()
+	stop()
+	log.Println("shutting down")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("failed to shut down gracefully: %v", err)
+	}
 }
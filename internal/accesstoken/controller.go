@@ -0,0 +1,88 @@
+package accesstoken
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type Controller struct {
+	service Service
+}
+
+func NewController(service Service) *Controller {
+	return &Controller{service: service}
+}
+
+func (controller *Controller) Create(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.IndentedJSON(401, gin.H{"error": "unauthorized"})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+
+	var dto CreateTokenRequest
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.IndentedJSON(400, gin.H{"error": "invalid request body: " + err.Error()})
+		return
+	}
+
+	token, err := controller.service.Create(c.Request.Context(), userUUID, &dto)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid expiry") {
+			c.IndentedJSON(400, gin.H{"error": err.Error()})
+			return
+		}
+		c.IndentedJSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.IndentedJSON(201, token)
+}
+
+func (controller *Controller) List(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.IndentedJSON(401, gin.H{"error": "unauthorized"})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+
+	tokens, err := controller.service.List(c.Request.Context(), userUUID)
+	if err != nil {
+		c.IndentedJSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.IndentedJSON(200, tokens)
+}
+
+func (controller *Controller) Revoke(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.IndentedJSON(401, gin.H{"error": "unauthorized"})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+
+	tokenID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.IndentedJSON(400, gin.H{"error": "invalid token ID"})
+		return
+	}
+
+	err = controller.service.Revoke(c.Request.Context(), userUUID, tokenID)
+	if err != nil {
+		if err.Error() == "token not found" {
+			c.IndentedJSON(404, gin.H{"error": err.Error()})
+			return
+		}
+		c.IndentedJSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.IndentedJSON(200, gin.H{"message": "token revoked"})
+}
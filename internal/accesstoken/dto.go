@@ -0,0 +1,43 @@
+package accesstoken
+
+import "time"
+
+type CreateTokenRequest struct {
+	Name      string     `json:"name" binding:"required,min=1,max=255"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1,dive,oneof=read-only tasks:write projects:admin"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type TokenResponse struct {
+	ID         int64      `json:"id"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreateTokenResponse is the only time the plaintext token is ever returned
+type CreateTokenResponse struct {
+	TokenResponse
+	Token string `json:"token"`
+}
+
+func ToTokenResponse(token *PersonalAccessToken) *TokenResponse {
+	return &TokenResponse{
+		ID:         token.ID,
+		Name:       token.Name,
+		Scopes:     token.ScopeList(),
+		LastUsedAt: token.LastUsedAt,
+		ExpiresAt:  token.ExpiresAt,
+		CreatedAt:  token.CreatedAt,
+	}
+}
+
+func ToTokenResponseList(tokens []*PersonalAccessToken) []*TokenResponse {
+	responses := make([]*TokenResponse, len(tokens))
+	for i, token := range tokens {
+		responses[i] = ToTokenResponse(token)
+	}
+	return responses
+}
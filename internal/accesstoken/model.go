@@ -0,0 +1,41 @@
+package accesstoken
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TokenPrefix marks personal access tokens so they can be told apart from JWTs
+const TokenPrefix = "pat_"
+
+// Valid scope values. Every scope allows reads; write scopes only unlock
+// mutations on the routes they are named after.
+const (
+	ScopeReadOnly      = "read-only"
+	ScopeTasksWrite    = "tasks:write"
+	ScopeProjectsAdmin = "projects:admin"
+)
+
+type PersonalAccessToken struct {
+	ID         int64      `gorm:"primaryKey;autoIncrement"`
+	UserID     uuid.UUID  `gorm:"type:uuid;not null;index"`
+	Name       string     `gorm:"type:varchar(255);not null"`
+	TokenHash  string     `gorm:"type:varchar(64);not null;uniqueIndex"`
+	Scopes     string     `gorm:"type:varchar(255);not null"`
+	LastUsedAt *time.Time `gorm:"type:timestamp"`
+	ExpiresAt  *time.Time `gorm:"type:timestamp"`
+	CreatedAt  time.Time  `gorm:"type:timestamp;not null"`
+}
+
+func (PersonalAccessToken) TableName() string {
+	return "personal_access_token"
+}
+
+func (t *PersonalAccessToken) ScopeList() []string {
+	if t.Scopes == "" {
+		return []string{}
+	}
+	return strings.Split(t.Scopes, ",")
+}
@@ -0,0 +1,67 @@
+package accesstoken
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) Create(ctx context.Context, token *PersonalAccessToken) error {
+	err := r.db.WithContext(ctx).Create(token).Error
+	if err != nil {
+		return fmt.Errorf("accesstoken - Create: %v", err)
+	}
+	return nil
+}
+
+func (r *Repository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*PersonalAccessToken, error) {
+	var tokens []*PersonalAccessToken
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").Find(&tokens).Error
+	if err != nil {
+		return nil, fmt.Errorf("FindByUserID: %v", err)
+	}
+	return tokens, nil
+}
+
+func (r *Repository) FindByHash(ctx context.Context, tokenHash string) (*PersonalAccessToken, error) {
+	var token PersonalAccessToken
+	err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("token not found")
+		}
+		return nil, fmt.Errorf("FindByHash: %v", err)
+	}
+	return &token, nil
+}
+
+func (r *Repository) TouchLastUsed(ctx context.Context, id int64, at time.Time) error {
+	err := r.db.WithContext(ctx).Model(&PersonalAccessToken{}).Where("id = ?", id).Update("last_used_at", at).Error
+	if err != nil {
+		return fmt.Errorf("TouchLastUsed: %v", err)
+	}
+	return nil
+}
+
+func (r *Repository) Delete(ctx context.Context, id int64, userID uuid.UUID) error {
+	res := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(&PersonalAccessToken{})
+	if res.Error != nil {
+		return fmt.Errorf("accesstoken - Delete: %v", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("token not found")
+	}
+	return nil
+}
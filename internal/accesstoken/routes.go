@@ -0,0 +1,18 @@
+package accesstoken
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRoutes(group *gin.RouterGroup, controller *Controller,
+	authMw gin.HandlerFunc, scopeMw gin.HandlerFunc, rateLimitMw gin.HandlerFunc) {
+	tokens := group.Group("/users/me/tokens")
+	tokens.Use(authMw)
+	tokens.Use(scopeMw)
+	tokens.Use(rateLimitMw)
+	{
+		tokens.GET("", controller.List)
+		tokens.POST("", controller.Create)
+		tokens.DELETE("/:id", controller.Revoke)
+	}
+}
@@ -0,0 +1,95 @@
+package accesstoken
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"task-management/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+// last_used_at is only written once per interval to keep authenticated
+// requests from turning into a write each
+const touchInterval = time.Minute
+
+type Service interface {
+	Create(ctx context.Context, userID uuid.UUID, dto *CreateTokenRequest) (*CreateTokenResponse, error)
+	List(ctx context.Context, userID uuid.UUID) ([]*TokenResponse, error)
+	Revoke(ctx context.Context, userID uuid.UUID, tokenID int64) error
+	Authenticate(ctx context.Context, rawToken string) (*PersonalAccessToken, error)
+}
+
+type service struct {
+	repo *Repository
+}
+
+func NewService(repo *Repository) Service {
+	return &service{repo: repo}
+}
+
+func (s *service) Create(ctx context.Context, userID uuid.UUID, dto *CreateTokenRequest) (*CreateTokenResponse, error) {
+	now := time.Now()
+	if dto.ExpiresAt != nil && !dto.ExpiresAt.After(now) {
+		return nil, fmt.Errorf("invalid expiry: expires_at must be in the future")
+	}
+
+	secret, _, err := utils.NewOpaqueToken()
+	if err != nil {
+		return nil, fmt.Errorf("Create: %v", err)
+	}
+	rawToken := TokenPrefix + secret
+
+	token := &PersonalAccessToken{
+		UserID:    userID,
+		Name:      dto.Name,
+		TokenHash: utils.HashToken(rawToken),
+		Scopes:    strings.Join(dto.Scopes, ","),
+		ExpiresAt: dto.ExpiresAt,
+		CreatedAt: now,
+	}
+
+	if err := s.repo.Create(ctx, token); err != nil {
+		return nil, err
+	}
+
+	return &CreateTokenResponse{
+		TokenResponse: *ToTokenResponse(token),
+		Token:         rawToken,
+	}, nil
+}
+
+func (s *service) List(ctx context.Context, userID uuid.UUID) ([]*TokenResponse, error) {
+	tokens, err := s.repo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return ToTokenResponseList(tokens), nil
+}
+
+func (s *service) Revoke(ctx context.Context, userID uuid.UUID, tokenID int64) error {
+	return s.repo.Delete(ctx, tokenID, userID)
+}
+
+func (s *service) Authenticate(ctx context.Context, rawToken string) (*PersonalAccessToken, error) {
+	token, err := s.repo.FindByHash(ctx, utils.HashToken(rawToken))
+	if err != nil {
+		return nil, fmt.Errorf("Authenticate: %v", err)
+	}
+
+	now := time.Now()
+	if token.ExpiresAt != nil && now.After(*token.ExpiresAt) {
+		return nil, fmt.Errorf("Authenticate: token expired")
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) > touchInterval {
+		if err := s.repo.TouchLastUsed(ctx, token.ID, now); err != nil {
+			return nil, fmt.Errorf("Authenticate: %v", err)
+		}
+		token.LastUsedAt = &now
+	}
+
+	return token, nil
+}
@@ -0,0 +1,85 @@
+package account
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type Controller struct {
+	service Service
+}
+
+func NewController(service Service) *Controller {
+	return &Controller{service: service}
+}
+
+// Export handles GET /users/me/export?format=json|zip. The routes keep
+// personal access tokens out; a full export needs an interactive login.
+func (controller *Controller) Export(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.IndentedJSON(401, gin.H{"error": "unauthorized"})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+
+	format := c.DefaultQuery("format", "json")
+	filename := fmt.Sprintf("task-management-export-%s", time.Now().Format("20060102"))
+
+	switch format {
+	case "json":
+		export, err := controller.service.Export(c.Request.Context(), userUUID)
+		if err != nil {
+			c.IndentedJSON(500, gin.H{"error": err.Error()})
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+".json"))
+		c.IndentedJSON(200, export)
+	case "zip":
+		archive, err := controller.service.ExportZip(c.Request.Context(), userUUID)
+		if err != nil {
+			c.IndentedJSON(500, gin.H{"error": err.Error()})
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+".zip"))
+		c.Data(200, "application/zip", archive)
+	default:
+		c.IndentedJSON(400, gin.H{"error": "format must be json or zip"})
+	}
+}
+
+func (controller *Controller) Delete(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.IndentedJSON(401, gin.H{"error": "unauthorized"})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+
+	var dto DeleteAccountRequest
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.IndentedJSON(400, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	res, err := controller.service.Delete(c.Request.Context(), userUUID, &dto)
+	if err != nil {
+		switch {
+		case err.Error() == "invalid password",
+			strings.Contains(err.Error(), "invalid two-factor code"):
+			c.IndentedJSON(401, gin.H{"error": err.Error()})
+		case err.Error() == "two-factor code required",
+			strings.Contains(err.Error(), "set a password first"):
+			c.IndentedJSON(400, gin.H{"error": err.Error()})
+		default:
+			c.IndentedJSON(500, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.IndentedJSON(200, res)
+}
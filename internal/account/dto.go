@@ -0,0 +1,56 @@
+package account
+
+import (
+	"time"
+
+	"task-management/internal/comment"
+	"task-management/internal/project"
+	"task-management/internal/security"
+	"task-management/internal/session"
+	"task-management/internal/subtask"
+	"task-management/internal/task"
+	"task-management/internal/user"
+)
+
+// What happens to the projects a deleted user owns
+const (
+	// PolicyTransfer hands each project to its highest-ranked, then
+	// longest-standing member and deletes projects without other members
+	PolicyTransfer = "transfer"
+	PolicyDelete   = "delete"
+)
+
+type DeleteAccountRequest struct {
+	// Password is required unless the account has none
+	Password      string `json:"password" binding:"max=255"`
+	Code          string `json:"code" binding:"omitempty,max=32"`
+	ProjectPolicy string `json:"project_policy" binding:"required,oneof=transfer delete"`
+}
+
+type DeleteAccountResponse struct {
+	ProjectsTransferred int `json:"projects_transferred"`
+	ProjectsDeleted     int `json:"projects_deleted"`
+}
+
+type ExportedProject struct {
+	*project.ProjectResponse
+	Tasks []*ExportedTask `json:"tasks"`
+}
+
+type ExportedTask struct {
+	*task.TaskResponse
+	Subtasks []subtask.SubtaskResponse `json:"subtasks"`
+}
+
+// Export is everything we store about a user that they can take with them
+type Export struct {
+	ExportedAt       time.Time                      `json:"exported_at"`
+	Profile          *user.UserResponse             `json:"profile"`
+	OwnedProjects    []*ExportedProject             `json:"owned_projects"`
+	MemberOf         []int64                        `json:"member_of_project_ids"`
+	AssignedTasks    []*task.TaskResponse           `json:"assigned_tasks"`
+	AssignedSubtasks []subtask.SubtaskResponse      `json:"assigned_subtasks"`
+	Comments         []*comment.CommentResponse     `json:"comments"`
+	Sessions         []*session.SessionResponse     `json:"sessions"`
+	LoginEvents      []*security.LoginEventResponse `json:"login_events"`
+}
@@ -0,0 +1,166 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"task-management/internal/comment"
+	"task-management/internal/project"
+	"task-management/internal/subtask"
+	"task-management/internal/task"
+	"task-management/internal/user"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Repository reads and removes a user's data across feature tables
+type Repository struct {
+	db *gorm.DB
+	// tasks loads task assignees the same way the task feature does
+	tasks *task.Repository
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{db: db, tasks: task.NewRepository(db)}
+}
+
+func (r *Repository) OwnedProjects(ctx context.Context, userID uuid.UUID) ([]*project.Project, error) {
+	var projects []*project.Project
+	err := r.db.WithContext(ctx).Where("owner_id = ?", userID).Order("id").Find(&projects).Error
+	if err != nil {
+		return nil, fmt.Errorf("OwnedProjects: %v", err)
+	}
+	return projects, nil
+}
+
+func (r *Repository) TasksByProjectIDs(ctx context.Context, projectIDs []int64) ([]*task.Task, error) {
+	var tasks []*task.Task
+	if len(projectIDs) == 0 {
+		return tasks, nil
+	}
+	err := r.db.WithContext(ctx).Where("project_id IN ?", projectIDs).Order("id").Find(&tasks).Error
+	if err != nil {
+		return nil, fmt.Errorf("TasksByProjectIDs: %v", err)
+	}
+	if err := r.tasks.LoadDetails(ctx, tasks); err != nil {
+		return nil, fmt.Errorf("TasksByProjectIDs: %v", err)
+	}
+	return tasks, nil
+}
+
+func (r *Repository) SubtasksByTaskIDs(ctx context.Context, taskIDs []int64) ([]subtask.Subtask, error) {
+	var subtasks []subtask.Subtask
+	if len(taskIDs) == 0 {
+		return subtasks, nil
+	}
+	err := r.db.WithContext(ctx).Where("task_id IN ?", taskIDs).Order("id").Find(&subtasks).Error
+	if err != nil {
+		return nil, fmt.Errorf("SubtasksByTaskIDs: %v", err)
+	}
+	return subtasks, nil
+}
+
+func (r *Repository) AssignedSubtasks(ctx context.Context, userID uuid.UUID) ([]subtask.Subtask, error) {
+	var subtasks []subtask.Subtask
+	err := r.db.WithContext(ctx).Where("assigned_to = ?", userID).Order("id").Find(&subtasks).Error
+	if err != nil {
+		return nil, fmt.Errorf("AssignedSubtasks: %v", err)
+	}
+	return subtasks, nil
+}
+
+func (r *Repository) AssignedTasks(ctx context.Context, userID uuid.UUID) ([]*task.Task, error) {
+	var tasks []*task.Task
+	err := r.db.WithContext(ctx).Joins("JOIN task_assignee ON task_assignee.task_id = task.id").
+		Where("task_assignee.user_id = ?", userID).Order("task.id").Find(&tasks).Error
+	if err != nil {
+		return nil, fmt.Errorf("AssignedTasks: %v", err)
+	}
+	if err := r.tasks.LoadDetails(ctx, tasks); err != nil {
+		return nil, fmt.Errorf("AssignedTasks: %v", err)
+	}
+	return tasks, nil
+}
+
+func (r *Repository) Comments(ctx context.Context, userID uuid.UUID) ([]*comment.Comment, error) {
+	var comments []*comment.Comment
+	err := r.db.WithContext(ctx).Where("author_id = ? AND deleted_at IS NULL", userID).Order("id").Find(&comments).Error
+	if err != nil {
+		return nil, fmt.Errorf("Comments: %v", err)
+	}
+	return comments, nil
+}
+
+func (r *Repository) MemberProjectIDs(ctx context.Context, userID uuid.UUID) ([]int64, error) {
+	var ids []int64
+	err := r.db.WithContext(ctx).Model(&project.ProjectMember{}).
+		Where("user_id = ?", userID).Order("project_id").Pluck("project_id", &ids).Error
+	if err != nil {
+		return nil, fmt.Errorf("MemberProjectIDs: %v", err)
+	}
+	return ids, nil
+}
+
+// DeleteUser removes the user in one transaction. Owned projects are handled
+// per policy, subtask assignments are cleared and the remaining per-user rows
+// (tokens, sessions, memberships, ...) go with the user through ON DELETE CASCADE.
+func (r *Repository) DeleteUser(ctx context.Context, userID uuid.UUID, policy string) (*DeleteAccountResponse, error) {
+	res := &DeleteAccountResponse{}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// projects in the trash are removed for good whatever the policy
+		var projects []*project.Project
+		if err := tx.Unscoped().Where("owner_id = ?", userID).Find(&projects).Error; err != nil {
+			return err
+		}
+
+		for _, p := range projects {
+			if policy == PolicyTransfer && !p.DeletedAt.Valid {
+				var heir project.ProjectMember
+				err := tx.Where("project_id = ? AND user_id <> ?", p.ID, userID).
+					Order("CASE role WHEN 'admin' THEN 0 WHEN 'maintainer' THEN 1 WHEN 'editor' THEN 2 ELSE 3 END, id").
+					First(&heir).Error
+				if err == nil {
+					if err := tx.Model(&project.Project{}).Where("id = ?", p.ID).Update("owner_id", heir.UserID).Error; err != nil {
+						return err
+					}
+					// owners are not listed as members of their own project
+					if err := tx.Delete(&project.ProjectMember{}, heir.ID).Error; err != nil {
+						return err
+					}
+					err := project.RecordEvent(tx, p.ID, userID, project.EventOwnershipTransferred, map[string]interface{}{
+						"previous_owner_id": userID,
+						"new_owner_id":      heir.UserID,
+						"reason":            "account_deleted",
+					})
+					if err != nil {
+						return err
+					}
+					res.ProjectsTransferred++
+					continue
+				}
+				if !errors.Is(err, gorm.ErrRecordNotFound) {
+					return err
+				}
+			}
+
+			if err := tx.Unscoped().Delete(&project.Project{}, p.ID).Error; err != nil {
+				return err
+			}
+			res.ProjectsDeleted++
+		}
+
+		if err := tx.Unscoped().Model(&subtask.Subtask{}).Where("assigned_to = ?", userID).Update("assigned_to", nil).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&user.User{}, "id = ?", userID).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("DeleteUser: %v", err)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,17 @@
+package account
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRoutes(group *gin.RouterGroup, controller *Controller,
+	authMw gin.HandlerFunc, scopeMw gin.HandlerFunc, rateLimitMw gin.HandlerFunc) {
+	account := group.Group("/users/me")
+	account.Use(authMw)
+	account.Use(scopeMw)
+	account.Use(rateLimitMw)
+	{
+		account.GET("/export", controller.Export)
+		account.DELETE("", controller.Delete)
+	}
+}
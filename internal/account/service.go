@@ -0,0 +1,227 @@
+package account
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"task-management/internal/comment"
+	"task-management/internal/password"
+	"task-management/internal/project"
+	"task-management/internal/security"
+	"task-management/internal/session"
+	"task-management/internal/subtask"
+	"task-management/internal/task"
+	"task-management/internal/twofactor"
+	"task-management/internal/user"
+
+	"github.com/google/uuid"
+)
+
+// exports include the most recent login events, not the full history
+const exportEventLimit = 100
+
+type Service interface {
+	Export(ctx context.Context, userID uuid.UUID) (*Export, error)
+	// ExportZip packs the export as one JSON file per section
+	ExportZip(ctx context.Context, userID uuid.UUID) ([]byte, error)
+	Delete(ctx context.Context, userID uuid.UUID, dto *DeleteAccountRequest) (*DeleteAccountResponse, error)
+}
+
+type service struct {
+	repo      *Repository
+	userRepo  *user.Repository
+	hasher    password.Hasher
+	twoFactor twofactor.Service
+	sessions  session.Service
+	security  security.Service
+}
+
+func NewService(repo *Repository, userRepo *user.Repository, hasher password.Hasher, twoFactor twofactor.Service,
+	sessions session.Service, security security.Service) Service {
+	return &service{
+		repo:      repo,
+		userRepo:  userRepo,
+		hasher:    hasher,
+		twoFactor: twoFactor,
+		sessions:  sessions,
+		security:  security,
+	}
+}
+
+func (s *service) Export(ctx context.Context, userID uuid.UUID) (*Export, error) {
+	userInfo, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("Export: %v", err)
+	}
+
+	projects, err := s.repo.OwnedProjects(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("Export: %v", err)
+	}
+	projectIDs := make([]int64, 0, len(projects))
+	for _, p := range projects {
+		projectIDs = append(projectIDs, p.ID)
+	}
+
+	tasks, err := s.repo.TasksByProjectIDs(ctx, projectIDs)
+	if err != nil {
+		return nil, fmt.Errorf("Export: %v", err)
+	}
+	taskIDs := make([]int64, 0, len(tasks))
+	for _, t := range tasks {
+		taskIDs = append(taskIDs, t.ID)
+	}
+
+	subtasks, err := s.repo.SubtasksByTaskIDs(ctx, taskIDs)
+	if err != nil {
+		return nil, fmt.Errorf("Export: %v", err)
+	}
+
+	assignedTasks, err := s.repo.AssignedTasks(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("Export: %v", err)
+	}
+
+	assigned, err := s.repo.AssignedSubtasks(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("Export: %v", err)
+	}
+
+	comments, err := s.repo.Comments(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("Export: %v", err)
+	}
+
+	memberOf, err := s.repo.MemberProjectIDs(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("Export: %v", err)
+	}
+
+	sessions, err := s.sessions.List(ctx, userID, uuid.Nil)
+	if err != nil {
+		return nil, fmt.Errorf("Export: %v", err)
+	}
+
+	events, err := s.security.RecentEvents(ctx, userID, exportEventLimit)
+	if err != nil {
+		return nil, fmt.Errorf("Export: %v", err)
+	}
+
+	subtasksByTask := map[int64][]subtask.SubtaskResponse{}
+	for i := range subtasks {
+		subtasksByTask[subtasks[i].TaskID] = append(subtasksByTask[subtasks[i].TaskID], subtask.ToSubtaskResponse(&subtasks[i]))
+	}
+
+	tasksByProject := map[int64][]*ExportedTask{}
+	for _, t := range tasks {
+		exported := &ExportedTask{TaskResponse: task.ToTaskResponse(t), Subtasks: subtasksByTask[t.ID]}
+		if exported.Subtasks == nil {
+			exported.Subtasks = []subtask.SubtaskResponse{}
+		}
+		tasksByProject[t.ProjectID] = append(tasksByProject[t.ProjectID], exported)
+	}
+
+	owned := make([]*ExportedProject, 0, len(projects))
+	for _, p := range projects {
+		exported := &ExportedProject{ProjectResponse: project.ToProjectResponse(p), Tasks: tasksByProject[p.ID]}
+		if exported.Tasks == nil {
+			exported.Tasks = []*ExportedTask{}
+		}
+		owned = append(owned, exported)
+	}
+
+	return &Export{
+		ExportedAt:       time.Now(),
+		Profile:          user.ToUserResponse(userInfo),
+		OwnedProjects:    owned,
+		MemberOf:         memberOf,
+		AssignedTasks:    task.ToTaskResponseList(assignedTasks),
+		AssignedSubtasks: subtask.ToSubtaskResponseList(assigned),
+		Comments:         comment.ToCommentResponseList(comments),
+		Sessions:         sessions,
+		LoginEvents:      events,
+	}, nil
+}
+
+func (s *service) ExportZip(ctx context.Context, userID uuid.UUID) ([]byte, error) {
+	export, err := s.Export(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"profile.json", export.Profile},
+		{"projects.json", export.OwnedProjects},
+		{"memberships.json", export.MemberOf},
+		{"assigned_tasks.json", export.AssignedTasks},
+		{"assigned_subtasks.json", export.AssignedSubtasks},
+		{"comments.json", export.Comments},
+		{"sessions.json", export.Sessions},
+		{"login_events.json", export.LoginEvents},
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, file := range files {
+		w, err := archive.CreateHeader(&zip.FileHeader{Name: file.name, Method: zip.Deflate, Modified: export.ExportedAt})
+		if err != nil {
+			return nil, fmt.Errorf("ExportZip: %v", err)
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(file.data); err != nil {
+			return nil, fmt.Errorf("ExportZip: %v", err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("ExportZip: %v", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (s *service) Delete(ctx context.Context, userID uuid.UUID, dto *DeleteAccountRequest) (*DeleteAccountResponse, error) {
+	userInfo, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("Delete: %v", err)
+	}
+
+	// accounts created through an identity provider have no password; a
+	// two-factor code alone proves it is them, otherwise they need to set one
+	if userInfo.PasswordHash != "" {
+		if !s.hasher.Verify(dto.Password, userInfo.PasswordHash) {
+			return nil, fmt.Errorf("invalid password")
+		}
+	} else if userInfo.TOTPEnabledAt == nil {
+		return nil, fmt.Errorf("account has no password - set a password first")
+	}
+
+	if userInfo.TOTPEnabledAt != nil {
+		if dto.Code == "" {
+			return nil, fmt.Errorf("two-factor code required")
+		}
+		if err := s.twoFactor.Verify(ctx, userID, dto.Code); err != nil {
+			return nil, fmt.Errorf("Delete: %v", err)
+		}
+	}
+
+	// kill outstanding access tokens first; the session rows themselves are
+	// removed with the user
+	if err := s.sessions.RevokeAllExcept(ctx, userID, uuid.Nil); err != nil {
+		return nil, fmt.Errorf("Delete: %v", err)
+	}
+
+	res, err := s.repo.DeleteUser(ctx, userID, dto.ProjectPolicy)
+	if err != nil {
+		return nil, fmt.Errorf("Delete: %v", err)
+	}
+
+	return res, nil
+}
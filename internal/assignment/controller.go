@@ -0,0 +1,68 @@
+package assignment
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type Controller struct {
+	service Service
+}
+
+func NewController(service Service) *Controller {
+	return &Controller{service: service}
+}
+
+// parseDate accepts a full timestamp or a plain date. A plain date as the
+// end of the window includes that whole day.
+func parseDate(raw string, end bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", raw)
+	if err != nil || !end {
+		return t, err
+	}
+	return t.AddDate(0, 0, 1), nil
+}
+
+// Mine lists what is assigned to the current user
+// GET /users/me/tasks?status=&priority=&due_from=&due_to=
+func (controller *Controller) Mine(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.IndentedJSON(401, gin.H{"error": "unauthorized"})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+
+	filters := make(map[string]interface{})
+	if status := c.Query("status"); status != "" {
+		filters["status"] = status
+	}
+	if priority := c.Query("priority"); priority != "" {
+		filters["priority"] = priority
+	}
+	for _, key := range []string{"due_from", "due_to"} {
+		raw := c.Query(key)
+		if raw == "" {
+			continue
+		}
+		parsed, err := parseDate(raw, key == "due_to")
+		if err != nil {
+			c.IndentedJSON(400, gin.H{"error": key + " must be a date (2006-01-02) or an RFC 3339 timestamp"})
+			return
+		}
+		filters[key] = parsed
+	}
+
+	assigned, err := controller.service.Mine(c.Request.Context(), userUUID, filters)
+	if err != nil {
+		c.IndentedJSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.IndentedJSON(200, assigned)
+}
@@ -0,0 +1,28 @@
+package assignment
+
+import (
+	"task-management/internal/subtask"
+	"task-management/internal/task"
+)
+
+type AssignedSubtaskResponse struct {
+	subtask.SubtaskResponse
+	ProjectID int64 `json:"project_id"`
+}
+
+// AssignedResponse is the work assigned to a user across their projects
+type AssignedResponse struct {
+	Tasks    []*task.TaskResponse       `json:"tasks"`
+	Subtasks []*AssignedSubtaskResponse `json:"subtasks"`
+}
+
+func ToAssignedSubtaskResponseList(subtasks []*AssignedSubtask) []*AssignedSubtaskResponse {
+	responses := make([]*AssignedSubtaskResponse, len(subtasks))
+	for i, st := range subtasks {
+		responses[i] = &AssignedSubtaskResponse{
+			SubtaskResponse: subtask.ToSubtaskResponse(&st.Subtask),
+			ProjectID:       st.ProjectID,
+		}
+	}
+	return responses
+}
@@ -0,0 +1,82 @@
+package assignment
+
+import (
+	"context"
+	"fmt"
+
+	"task-management/internal/subtask"
+	"task-management/internal/task"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AssignedSubtask is a subtask with the project its task belongs to
+type AssignedSubtask struct {
+	subtask.Subtask `gorm:"embedded"`
+	ProjectID       int64
+}
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{db: db}
+}
+
+// filter applies the status, priority and due window filters to table's columns
+func filter(query *gorm.DB, table string, filters map[string]interface{}) *gorm.DB {
+	if status, ok := filters["status"]; ok {
+		query = query.Where(table+".status = ?", status)
+	}
+	if priority, ok := filters["priority"]; ok {
+		query = query.Where(table+".priority = ?", priority)
+	}
+	if from, ok := filters["due_from"]; ok {
+		query = query.Where(table+".due_date >= ?", from)
+	}
+	if to, ok := filters["due_to"]; ok {
+		query = query.Where(table+".due_date < ?", to)
+	}
+	return query
+}
+
+// Tasks lists the tasks assigned to the user outside archived and trashed
+// projects, the ones due first at the top
+func (r *Repository) Tasks(ctx context.Context, userID uuid.UUID, filters map[string]interface{}) ([]*task.Task, error) {
+	var tasks []*task.Task
+	query := r.db.WithContext(ctx).
+		Joins("JOIN task_assignee ON task_assignee.task_id = task.id").
+		Joins("JOIN project ON project.id = task.project_id").
+		Where("task_assignee.user_id = ?", userID).
+		Where("project.deleted_at IS NULL AND project.archived_at IS NULL")
+
+	err := filter(query, "task", filters).
+		Order("task.due_date ASC NULLS LAST, task.id").
+		Find(&tasks).Error
+	if err != nil {
+		return nil, fmt.Errorf("Tasks: %v", err)
+	}
+	return tasks, nil
+}
+
+// Subtasks lists the subtasks assigned to the user, ordered like Tasks
+func (r *Repository) Subtasks(ctx context.Context, userID uuid.UUID, filters map[string]interface{}) ([]*AssignedSubtask, error) {
+	var subtasks []*AssignedSubtask
+	query := r.db.WithContext(ctx).Table("subtask").
+		Select("subtask.*, task.project_id").
+		Joins("JOIN task ON task.id = subtask.task_id").
+		Joins("JOIN project ON project.id = task.project_id").
+		Where("subtask.assigned_to = ?", userID).
+		Where("subtask.deleted_at IS NULL AND task.deleted_at IS NULL").
+		Where("project.deleted_at IS NULL AND project.archived_at IS NULL")
+
+	err := filter(query, "subtask", filters).
+		Order("subtask.due_date ASC NULLS LAST, subtask.id").
+		Scan(&subtasks).Error
+	if err != nil {
+		return nil, fmt.Errorf("Subtasks: %v", err)
+	}
+	return subtasks, nil
+}
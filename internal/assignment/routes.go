@@ -0,0 +1,16 @@
+package assignment
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRoutes(group *gin.RouterGroup, controller *Controller,
+	authMw gin.HandlerFunc, scopeMw gin.HandlerFunc, rateLimitMw gin.HandlerFunc) {
+	users := group.Group("/users")
+	users.Use(authMw)
+	users.Use(scopeMw)
+	users.Use(rateLimitMw)
+	{
+		users.GET("/me/tasks", controller.Mine)
+	}
+}
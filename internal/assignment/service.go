@@ -0,0 +1,44 @@
+package assignment
+
+import (
+	"context"
+	"fmt"
+
+	"task-management/internal/task"
+
+	"github.com/google/uuid"
+)
+
+type Service interface {
+	// Mine lists the tasks and subtasks assigned to the user
+	Mine(ctx context.Context, userID uuid.UUID, filters map[string]interface{}) (*AssignedResponse, error)
+}
+
+type service struct {
+	repo     *Repository
+	taskRepo *task.Repository
+}
+
+func NewService(repo *Repository, taskRepo *task.Repository) Service {
+	return &service{repo: repo, taskRepo: taskRepo}
+}
+
+func (s *service) Mine(ctx context.Context, userID uuid.UUID, filters map[string]interface{}) (*AssignedResponse, error) {
+	tasks, err := s.repo.Tasks(ctx, userID, filters)
+	if err != nil {
+		return nil, fmt.Errorf("Mine: %v", err)
+	}
+	if err := s.taskRepo.LoadDetails(ctx, tasks); err != nil {
+		return nil, fmt.Errorf("Mine: %v", err)
+	}
+
+	subtasks, err := s.repo.Subtasks(ctx, userID, filters)
+	if err != nil {
+		return nil, fmt.Errorf("Mine: %v", err)
+	}
+
+	return &AssignedResponse{
+		Tasks:    task.ToTaskResponseList(tasks),
+		Subtasks: ToAssignedSubtaskResponseList(subtasks),
+	}, nil
+}
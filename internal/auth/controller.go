@@ -1,7 +1,16 @@
 package auth
 
 import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"task-management/internal/password"
+	"task-management/internal/security"
+	"task-management/internal/session"
+
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 type AuthController struct {
@@ -12,6 +21,19 @@ func NewAuthController(service AuthService) *AuthController {
 	return &AuthController{service: service}
 }
 
+func clientInfo(c *gin.Context) *session.ClientInfo {
+	return &session.ClientInfo{IP: c.ClientIP(), UserAgent: c.Request.UserAgent()}
+}
+
+func respondLocked(c *gin.Context, locked *security.LockedError) {
+	retryAfter := int(locked.RetryAfter.Seconds())
+	c.Header("Retry-After", fmt.Sprintf("%d", retryAfter))
+	c.IndentedJSON(429, gin.H{
+		"error":       "account temporarily locked",
+		"retry_after": retryAfter,
+	})
+}
+
 func (controller *AuthController) Register(c *gin.Context) {
 	var dto RegisterRequest
 	if err := c.ShouldBindJSON(&dto); err != nil {
@@ -21,8 +43,15 @@ func (controller *AuthController) Register(c *gin.Context) {
 		return
 	}
 
-	res, err := controller.service.Register(c.Request.Context(), &dto)
+	res, err := controller.service.Register(c.Request.Context(), &dto, clientInfo(c))
 	if err != nil {
+		var rejected *password.PolicyError
+		if errors.As(err, &rejected) {
+			c.IndentedJSON(422, gin.H{
+				"error": rejected.Error(),
+			})
+			return
+		}
 		if err.Error() == "email already registered" {
 			c.IndentedJSON(409, gin.H{
 				"error": err.Error(),
@@ -48,8 +77,13 @@ func (controller *AuthController) Login(c *gin.Context) {
 		return
 	}
 
-	res, err := controller.service.Login(c.Request.Context(), &dto)
+	res, err := controller.service.Login(c.Request.Context(), &dto, clientInfo(c))
 	if err != nil {
+		var locked *security.LockedError
+		if errors.As(err, &locked) {
+			respondLocked(c, locked)
+			return
+		}
 		c.IndentedJSON(401, gin.H{
 			"error": err.Error(),
 		})
@@ -58,3 +92,207 @@ func (controller *AuthController) Login(c *gin.Context) {
 
 	c.IndentedJSON(200, res)
 }
+
+func (controller *AuthController) LoginTwoFactor(c *gin.Context) {
+	var dto TwoFactorLoginRequest
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.IndentedJSON(400, gin.H{
+			"error": "invalid request body",
+		})
+		return
+	}
+
+	res, err := controller.service.LoginTwoFactor(c.Request.Context(), &dto, clientInfo(c))
+	if err != nil {
+		var locked *security.LockedError
+		if errors.As(err, &locked) {
+			respondLocked(c, locked)
+			return
+		}
+		if strings.Contains(err.Error(), "invalid challenge token") || strings.Contains(err.Error(), "invalid two-factor code") {
+			c.IndentedJSON(401, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		c.IndentedJSON(500, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.IndentedJSON(200, res)
+}
+
+func (controller *AuthController) Refresh(c *gin.Context) {
+	var dto RefreshRequest
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.IndentedJSON(400, gin.H{
+			"error": "invalid request body",
+		})
+		return
+	}
+
+	res, err := controller.service.Refresh(c.Request.Context(), &dto)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid refresh token") {
+			c.IndentedJSON(401, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		c.IndentedJSON(500, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.IndentedJSON(200, res)
+}
+
+func (controller *AuthController) Logout(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.IndentedJSON(401, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+	sessionID, _ := c.Get("sessionID")
+	sessionUUID, _ := sessionID.(uuid.UUID)
+	tokenID := c.GetString("tokenID")
+	tokenExpiresAt := c.GetTime("tokenExpiresAt")
+
+	var dto LogoutRequest
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.IndentedJSON(400, gin.H{
+			"error": "invalid request body",
+		})
+		return
+	}
+
+	err := controller.service.Logout(c.Request.Context(), userUUID, sessionUUID, tokenID, tokenExpiresAt, &dto)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid refresh token") {
+			c.IndentedJSON(400, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		c.IndentedJSON(500, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.IndentedJSON(200, gin.H{"message": "logged out successfully"})
+}
+
+func (controller *AuthController) ForgotPassword(c *gin.Context) {
+	var dto ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.IndentedJSON(400, gin.H{
+			"error": "invalid request body",
+		})
+		return
+	}
+
+	if err := controller.service.ForgotPassword(c.Request.Context(), &dto); err != nil {
+		c.IndentedJSON(500, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.IndentedJSON(200, gin.H{"message": "if the email is registered, a reset token has been sent"})
+}
+
+func (controller *AuthController) ResetPassword(c *gin.Context) {
+	var dto ResetPasswordRequest
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.IndentedJSON(400, gin.H{
+			"error": "invalid request body",
+		})
+		return
+	}
+
+	if err := controller.service.ResetPassword(c.Request.Context(), &dto); err != nil {
+		var rejected *password.PolicyError
+		if errors.As(err, &rejected) {
+			c.IndentedJSON(422, gin.H{
+				"error": rejected.Error(),
+			})
+			return
+		}
+		if strings.Contains(err.Error(), "token invalid or expired") {
+			c.IndentedJSON(400, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		c.IndentedJSON(500, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.IndentedJSON(200, gin.H{"message": "password has been reset"})
+}
+
+func (controller *AuthController) VerifyEmail(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		c.IndentedJSON(400, gin.H{
+			"error": "token is required",
+		})
+		return
+	}
+
+	if err := controller.service.VerifyEmail(c.Request.Context(), token); err != nil {
+		if strings.Contains(err.Error(), "token invalid or expired") {
+			c.IndentedJSON(400, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		c.IndentedJSON(500, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.IndentedJSON(200, gin.H{"message": "email verified"})
+}
+
+func (controller *AuthController) ResendVerification(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.IndentedJSON(401, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+
+	if err := controller.service.ResendVerification(c.Request.Context(), userUUID); err != nil {
+		if err.Error() == "email already verified" {
+			c.IndentedJSON(409, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		c.IndentedJSON(500, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.IndentedJSON(200, gin.H{"message": "verification email sent"})
+}
+
+// JWKS publishes the keys other services need to verify our access tokens
+func (controller *AuthController) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.IndentedJSON(200, controller.service.JWKS())
+}
@@ -1,18 +1,48 @@
 package auth
 
 type RegisterRequest struct {
-	Email    string `json:"email" binding:"required,email,min=1,max=255"`
-	Name     string `json:"name" binding:"required,min=1,max=255"`
-	Password string `json:"password" binding:"required,min=1,max=255"`
+	Email      string `json:"email" binding:"required,email,min=1,max=255"`
+	Name       string `json:"name" binding:"required,min=1,max=255"`
+	Password   string `json:"password" binding:"required,min=1,max=255"`
+	DeviceName string `json:"device_name" binding:"omitempty,max=255"`
 }
 
 type LoginRequest struct {
-	Email    string `json:"email" binding:"required,min=1,max=255"`
-	Password string `json:"password" binding:"required,min=1,max=255"`
+	Email      string `json:"email" binding:"required,min=1,max=255"`
+	Password   string `json:"password" binding:"required,min=1,max=255"`
+	DeviceName string `json:"device_name" binding:"omitempty,max=255"`
+}
+
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required,min=6,max=32"`
+	DeviceName     string `json:"device_name" binding:"omitempty,max=255"`
 }
 
-// AuthResponse represents the response after successful login/register
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// AuthResponse represents the response after successful login/register.
+// When the account has two-factor enabled, login only returns a challenge
+// token that has to be exchanged at /auth/login/2fa.
 type AuthResponse struct {
-	Token string      `json:"token"`
-	User  interface{} `json:"user"`
+	Token             string      `json:"token,omitempty"`
+	RefreshToken      string      `json:"refresh_token,omitempty"`
+	User              interface{} `json:"user,omitempty"`
+	TwoFactorRequired bool        `json:"two_factor_required,omitempty"`
+	ChallengeToken    string      `json:"challenge_token,omitempty"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email,max=255"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=1,max=255"`
 }
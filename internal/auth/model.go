@@ -0,0 +1,21 @@
+package auth
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RefreshToken struct {
+	ID        int64      `gorm:"primaryKey;autoIncrement"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index"`
+	FamilyID  uuid.UUID  `gorm:"type:uuid;not null;index"`
+	TokenHash string     `gorm:"type:varchar(64);not null;uniqueIndex"`
+	ExpiresAt time.Time  `gorm:"type:timestamp;not null"`
+	RevokedAt *time.Time `gorm:"type:timestamp"`
+	CreatedAt time.Time  `gorm:"type:timestamp;not null"`
+}
+
+func (RefreshToken) TableName() string {
+	return "refresh_token"
+}
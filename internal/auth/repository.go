@@ -0,0 +1,71 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) CreateRefreshToken(ctx context.Context, token *RefreshToken) error {
+	err := r.db.WithContext(ctx).Create(token).Error
+	if err != nil {
+		return fmt.Errorf("CreateRefreshToken: %v", err)
+	}
+	return nil
+}
+
+func (r *Repository) FindRefreshToken(ctx context.Context, tokenHash string) (*RefreshToken, error) {
+	var token RefreshToken
+	err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("refresh token not found")
+		}
+		return nil, fmt.Errorf("FindRefreshToken: %v", err)
+	}
+	return &token, nil
+}
+
+// RevokeRefreshToken marks a single token as used. It reports false when the
+// token had already been revoked, so concurrent refreshes cannot both win.
+func (r *Repository) RevokeRefreshToken(ctx context.Context, id int64) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&RefreshToken{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now())
+	if res.Error != nil {
+		return false, fmt.Errorf("RevokeRefreshToken: %v", res.Error)
+	}
+	return res.RowsAffected == 1, nil
+}
+
+func (r *Repository) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	err := r.db.WithContext(ctx).Model(&RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+	if err != nil {
+		return fmt.Errorf("RevokeFamily: %v", err)
+	}
+	return nil
+}
+
+func (r *Repository) RevokeAllForUser(ctx context.Context, userID uuid.UUID) error {
+	err := r.db.WithContext(ctx).Model(&RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+	if err != nil {
+		return fmt.Errorf("RevokeAllForUser: %v", err)
+	}
+	return nil
+}
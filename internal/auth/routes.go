@@ -4,9 +4,19 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func RegisterRoutes(group *gin.RouterGroup, controller *AuthController, rateLimit gin.HandlerFunc) {
+func RegisterRoutes(group *gin.RouterGroup, controller *AuthController,
+	authMw gin.HandlerFunc, scopeMw gin.HandlerFunc, rateLimit gin.HandlerFunc) {
+	group.GET("/.well-known/jwks.json", controller.JWKS)
+
 	auth := group.Group("/auth")
 	auth.Use(rateLimit)
 	auth.POST("/register", controller.Register)
 	auth.POST("/login", controller.Login)
+	auth.POST("/login/2fa", controller.LoginTwoFactor)
+	auth.POST("/refresh", controller.Refresh)
+	auth.POST("/logout", authMw, scopeMw, controller.Logout)
+	auth.POST("/password/forgot", controller.ForgotPassword)
+	auth.POST("/password/reset", controller.ResetPassword)
+	auth.GET("/verify", controller.VerifyEmail)
+	auth.POST("/verify/resend", authMw, scopeMw, controller.ResendVerification)
 }
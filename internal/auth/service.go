@@ -3,30 +3,117 @@ package auth
 import (
 	"context"
 	"fmt"
+	"log"
 	"time"
 
 	"task-management/internal/config"
+	"task-management/internal/mailer"
+	"task-management/internal/password"
+	"task-management/internal/security"
+	"task-management/internal/session"
+	"task-management/internal/twofactor"
 	"task-management/internal/user"
+	"task-management/internal/usertoken"
 	"task-management/internal/utils"
 
-	"golang.org/x/crypto/bcrypt"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
 )
 
 type AuthService interface {
-	Register(ctx context.Context, dto *RegisterRequest) (*AuthResponse, error)
-	Login(ctx context.Context, dto *LoginRequest) (*AuthResponse, error)
+	Register(ctx context.Context, dto *RegisterRequest, client *session.ClientInfo) (*AuthResponse, error)
+	Login(ctx context.Context, dto *LoginRequest, client *session.ClientInfo) (*AuthResponse, error)
+	LoginTwoFactor(ctx context.Context, dto *TwoFactorLoginRequest, client *session.ClientInfo) (*AuthResponse, error)
+	// LoginExternal finishes a login whose credentials were checked elsewhere, e.g. by an OIDC provider
+	LoginExternal(ctx context.Context, userInfo *user.User, client *session.ClientInfo) (*AuthResponse, error)
+	Refresh(ctx context.Context, dto *RefreshRequest) (*AuthResponse, error)
+	// Logout ends the session the access token belongs to; the refresh token
+	// posted must be from that same session
+	Logout(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID, tokenID string, tokenExpiresAt time.Time, dto *LogoutRequest) error
+	ForgotPassword(ctx context.Context, dto *ForgotPasswordRequest) error
+	ResetPassword(ctx context.Context, dto *ResetPasswordRequest) error
+	VerifyEmail(ctx context.Context, token string) error
+	ResendVerification(ctx context.Context, userID uuid.UUID) error
+	JWKS() *utils.JWKS
 }
 
 type authService struct {
-	config *config.Config
-	repo   *user.Repository
+	config    *config.Config
+	keys      *utils.KeySet
+	repo      *user.Repository
+	authRepo  *Repository
+	tokenRepo *usertoken.Repository
+	mailer    mailer.Mailer
+	hasher    password.Hasher
+	policy    *password.Policy
+	twoFactor twofactor.Service
+	security  security.Service
+	sessions  session.Service
+	rdb       *redis.Client
 }
 
-func NewAuthService(config *config.Config, repo *user.Repository) AuthService {
-	return &authService{config: config, repo: repo}
+func NewAuthService(config *config.Config, keys *utils.KeySet, repo *user.Repository, authRepo *Repository,
+	tokenRepo *usertoken.Repository, mailer mailer.Mailer, hasher password.Hasher, policy *password.Policy,
+	twoFactor twofactor.Service, security security.Service, sessions session.Service, rdb *redis.Client) AuthService {
+	return &authService{
+		config:    config,
+		keys:      keys,
+		repo:      repo,
+		authRepo:  authRepo,
+		tokenRepo: tokenRepo,
+		mailer:    mailer,
+		hasher:    hasher,
+		policy:    policy,
+		twoFactor: twoFactor,
+		security:  security,
+		sessions:  sessions,
+		rdb:       rdb,
+	}
 }
 
-func (s *authService) Register(ctx context.Context, dto *RegisterRequest) (*AuthResponse, error) {
+// startSession records a new device session and issues its first token pair
+func (s *authService) startSession(ctx context.Context, userInfo *user.User, client *session.ClientInfo) (*AuthResponse, error) {
+	newSession, err := s.sessions.Create(ctx, userInfo.ID, client)
+	if err != nil {
+		return nil, fmt.Errorf("startSession: %v", err)
+	}
+
+	return s.issueTokens(ctx, userInfo, newSession.ID)
+}
+
+// issueTokens creates an access token and a refresh token for the session;
+// the session ID is also the refresh token family
+func (s *authService) issueTokens(ctx context.Context, userInfo *user.User, sessionID uuid.UUID) (*AuthResponse, error) {
+	accessToken, err := s.keys.CreateToken(userInfo.ID, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("issueTokens: %v", err)
+	}
+
+	refreshToken, refreshHash, err := utils.NewOpaqueToken()
+	if err != nil {
+		return nil, fmt.Errorf("issueTokens: %v", err)
+	}
+
+	now := time.Now()
+	err = s.authRepo.CreateRefreshToken(ctx, &RefreshToken{
+		UserID:    userInfo.ID,
+		FamilyID:  sessionID,
+		TokenHash: refreshHash,
+		ExpiresAt: now.Add(time.Hour * time.Duration(s.config.JWT.RefreshExpireHours)),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("issueTokens: %v", err)
+	}
+
+	return &AuthResponse{
+		Token:        accessToken,
+		RefreshToken: refreshToken,
+		User:         user.ToUserResponse(userInfo),
+	}, nil
+}
+
+func (s *authService) Register(ctx context.Context, dto *RegisterRequest, client *session.ClientInfo) (*AuthResponse, error) {
 	available, err := s.repo.EmailAvailale(ctx, dto.Email)
 	if err != nil {
 		return nil, fmt.Errorf("Register: %v", err)
@@ -35,14 +122,18 @@ func (s *authService) Register(ctx context.Context, dto *RegisterRequest) (*Auth
 		return nil, fmt.Errorf("email already registered")
 	}
 
-	pwHash, err := bcrypt.GenerateFromPassword([]byte(dto.Password), bcrypt.DefaultCost)
+	if err := s.policy.Validate(dto.Password); err != nil {
+		return nil, fmt.Errorf("Register: %w", err)
+	}
+
+	pwHash, err := s.hasher.Hash(dto.Password)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate password hash - Register: %v", err)
 	}
 
 	new := &user.User{
 		Name:         dto.Name,
-		PasswordHash: string(pwHash),
+		PasswordHash: pwHash,
 		Email:        dto.Email,
 		CreatedAt:    time.Now(),
 	}
@@ -52,37 +143,449 @@ func (s *authService) Register(ctx context.Context, dto *RegisterRequest) (*Auth
 		return nil, fmt.Errorf("failed to add user to DB - Register: %v", err)
 	}
 
-	tokenString, err := utils.CreateToken(s.config, new.ID)
+	if err := s.sendVerification(ctx, new); err != nil {
+		log.Printf("failed to send verification mail - Register: %v", err)
+	}
+
+	client.DeviceName = dto.DeviceName
+	res, err := s.startSession(ctx, new, client)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create new token - Register: %v", err)
 	}
 
-	return &AuthResponse{
-		Token: tokenString,
-		User:  user.UserResponse{new.ID, new.Name, new.Email},
-	}, nil
+	return res, nil
 
 }
 
-func (s *authService) Login(ctx context.Context, dto *LoginRequest) (*AuthResponse, error) {
+func (s *authService) Login(ctx context.Context, dto *LoginRequest, client *session.ClientInfo) (*AuthResponse, error) {
+	event := &security.LoginEvent{Email: dto.Email, IPAddress: client.IP, UserAgent: client.UserAgent}
+
+	if err := s.security.CheckLockout(ctx, dto.Email); err != nil {
+		event.Outcome = security.OutcomeLocked
+		s.security.RecordEvent(ctx, event)
+		return nil, fmt.Errorf("Login: %w", err)
+	}
 
 	userInfo, err := s.repo.FindByEmail(ctx, dto.Email)
 	if err != nil {
-		return nil, fmt.Errorf("Login: %v", err)
+		if err.Error() != "user not found" {
+			return nil, fmt.Errorf("Login: %v", err)
+		}
+		return nil, s.loginFailed(ctx, event)
+	}
+	event.UserID = &userInfo.ID
+
+	if !s.hasher.Verify(dto.Password, userInfo.PasswordHash) {
+		return nil, s.loginFailed(ctx, event)
+	}
+	s.rehashIfNeeded(ctx, userInfo, dto.Password)
+
+	if userInfo.TOTPEnabledAt != nil {
+		challenge, err := s.keys.CreateChallengeToken(userInfo.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create challenge - Login: %v", err)
+		}
+		event.Outcome = security.OutcomeTwoFactorRequired
+		s.security.RecordEvent(ctx, event)
+		return &AuthResponse{TwoFactorRequired: true, ChallengeToken: challenge}, nil
 	}
 
-	if err = bcrypt.CompareHashAndPassword([]byte(userInfo.PasswordHash), []byte(dto.Password)); err != nil {
-		return nil, fmt.Errorf("password doesnt match - Login: %v", err)
+	if err := s.security.ResetFailures(ctx, dto.Email); err != nil {
+		return nil, fmt.Errorf("Login: %v", err)
 	}
 
-	token, err := utils.CreateToken(s.config, userInfo.ID)
+	client.DeviceName = dto.DeviceName
+	res, err := s.startSession(ctx, userInfo, client)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create token - Login: %v", err)
 	}
 
-	return &AuthResponse{
-		Token: token,
-		User:  user.UserResponse{userInfo.ID, userInfo.Name, userInfo.Email},
-	}, nil
+	event.Outcome = security.OutcomeSuccess
+	s.security.RecordEvent(ctx, event)
+
+	return res, nil
+
+}
+
+func (s *authService) LoginExternal(ctx context.Context, userInfo *user.User, client *session.ClientInfo) (*AuthResponse, error) {
+	event := &security.LoginEvent{
+		UserID:    &userInfo.ID,
+		Email:     userInfo.Email,
+		IPAddress: client.IP,
+		UserAgent: client.UserAgent,
+	}
+
+	if userInfo.TOTPEnabledAt != nil {
+		challenge, err := s.keys.CreateChallengeToken(userInfo.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create challenge - LoginExternal: %v", err)
+		}
+		event.Outcome = security.OutcomeTwoFactorRequired
+		s.security.RecordEvent(ctx, event)
+		return &AuthResponse{TwoFactorRequired: true, ChallengeToken: challenge}, nil
+	}
+
+	res, err := s.startSession(ctx, userInfo, client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token - LoginExternal: %v", err)
+	}
+
+	event.Outcome = security.OutcomeSuccess
+	s.security.RecordEvent(ctx, event)
+
+	return res, nil
+}
+
+// rehashIfNeeded upgrades hashes from an older algorithm or older parameters
+// while the plain password is at hand. Failing to do so must not fail the login.
+func (s *authService) rehashIfNeeded(ctx context.Context, userInfo *user.User, plain string) {
+	if !s.hasher.NeedsRehash(userInfo.PasswordHash) {
+		return
+	}
+
+	pwHash, err := s.hasher.Hash(plain)
+	if err != nil {
+		log.Printf("failed to rehash password - rehashIfNeeded: %v", err)
+		return
+	}
+	if err := s.repo.UpdatePassword(ctx, userInfo.ID, pwHash); err != nil {
+		log.Printf("failed to store rehashed password - rehashIfNeeded: %v", err)
+		return
+	}
+	userInfo.PasswordHash = pwHash
+}
+
+// loginFailed counts the failure toward the lockout and returns the same
+// error for unknown emails and wrong passwords
+func (s *authService) loginFailed(ctx context.Context, event *security.LoginEvent) error {
+	if event.Outcome == "" {
+		event.Outcome = security.OutcomeInvalidCredentials
+	}
+	s.security.RecordEvent(ctx, event)
+
+	if err := s.security.RegisterFailure(ctx, event.Email); err != nil {
+		return fmt.Errorf("Login: %v", err)
+	}
+
+	return fmt.Errorf("invalid email or password - Login")
+}
+
+func (s *authService) LoginTwoFactor(ctx context.Context, dto *TwoFactorLoginRequest, client *session.ClientInfo) (*AuthResponse, error) {
+	claims, err := s.keys.ParseToken(dto.ChallengeToken)
+	if err != nil || claims.Purpose != utils.PurposeTwoFactorChallenge {
+		return nil, fmt.Errorf("invalid challenge token - LoginTwoFactor")
+	}
+
+	userInfo, err := s.repo.FindByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("LoginTwoFactor: %v", err)
+	}
+
+	event := &security.LoginEvent{
+		UserID:    &userInfo.ID,
+		Email:     userInfo.Email,
+		IPAddress: client.IP,
+		UserAgent: client.UserAgent,
+	}
+
+	if err := s.security.CheckLockout(ctx, userInfo.Email); err != nil {
+		event.Outcome = security.OutcomeLocked
+		s.security.RecordEvent(ctx, event)
+		return nil, fmt.Errorf("LoginTwoFactor: %w", err)
+	}
+
+	used, err := utils.IsTokenRevoked(ctx, s.rdb, claims.ID)
+	if err != nil {
+		return nil, fmt.Errorf("LoginTwoFactor: %v", err)
+	}
+	if used {
+		return nil, fmt.Errorf("invalid challenge token - LoginTwoFactor")
+	}
+
+	if err := s.twoFactor.Verify(ctx, claims.UserID, dto.Code); err != nil {
+		if err.Error() != "invalid two-factor code" {
+			return nil, fmt.Errorf("LoginTwoFactor: %v", err)
+		}
+		event.Outcome = security.OutcomeTwoFactorFailed
+		s.security.RecordEvent(ctx, event)
+		if err := s.security.RegisterFailure(ctx, userInfo.Email); err != nil {
+			return nil, fmt.Errorf("LoginTwoFactor: %v", err)
+		}
+		return nil, fmt.Errorf("LoginTwoFactor: invalid two-factor code")
+	}
+
+	// a challenge can only be exchanged once
+	if err := utils.RevokeToken(ctx, s.rdb, claims.ID, claims.ExpiresAt.Time); err != nil {
+		return nil, fmt.Errorf("LoginTwoFactor: %v", err)
+	}
+
+	if err := s.security.ResetFailures(ctx, userInfo.Email); err != nil {
+		return nil, fmt.Errorf("LoginTwoFactor: %v", err)
+	}
+
+	client.DeviceName = dto.DeviceName
+	res, err := s.startSession(ctx, userInfo, client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token - LoginTwoFactor: %v", err)
+	}
+
+	event.Outcome = security.OutcomeSuccess
+	s.security.RecordEvent(ctx, event)
+
+	return res, nil
+}
+
+func (s *authService) Refresh(ctx context.Context, dto *RefreshRequest) (*AuthResponse, error) {
+	stored, err := s.authRepo.FindRefreshToken(ctx, utils.HashToken(dto.RefreshToken))
+	if err != nil {
+		return nil, fmt.Errorf("invalid refresh token - Refresh: %v", err)
+	}
+
+	// a revoked token being presented again means it was stolen or replayed,
+	// so the whole family is burned and the user has to log in again
+	if stored.RevokedAt != nil {
+		if err := s.burnFamily(ctx, stored); err != nil {
+			return nil, fmt.Errorf("Refresh: %v", err)
+		}
+		return nil, fmt.Errorf("invalid refresh token - Refresh: token reuse detected")
+	}
+
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, fmt.Errorf("invalid refresh token - Refresh: token expired")
+	}
+
+	if err := s.sessions.Validate(ctx, stored.FamilyID); err != nil {
+		return nil, fmt.Errorf("invalid refresh token - Refresh: %v", err)
+	}
+
+	rotated, err := s.authRepo.RevokeRefreshToken(ctx, stored.ID)
+	if err != nil {
+		return nil, fmt.Errorf("Refresh: %v", err)
+	}
+	if !rotated {
+		if err := s.burnFamily(ctx, stored); err != nil {
+			return nil, fmt.Errorf("Refresh: %v", err)
+		}
+		return nil, fmt.Errorf("invalid refresh token - Refresh: token reuse detected")
+	}
+
+	userInfo, err := s.repo.FindByID(ctx, stored.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("Refresh: %v", err)
+	}
+
+	res, err := s.issueTokens(ctx, userInfo, stored.FamilyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token - Refresh: %v", err)
+	}
+
+	return res, nil
+}
+
+// burnFamily ends the session a replayed refresh token belongs to
+func (s *authService) burnFamily(ctx context.Context, stored *RefreshToken) error {
+	if err := s.authRepo.RevokeFamily(ctx, stored.FamilyID); err != nil {
+		return err
+	}
+	if err := s.sessions.Revoke(ctx, stored.UserID, stored.FamilyID); err != nil && err.Error() != "session not found" {
+		return err
+	}
+	return nil
+}
+
+func (s *authService) Logout(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID, tokenID string, tokenExpiresAt time.Time, dto *LogoutRequest) error {
+	stored, err := s.authRepo.FindRefreshToken(ctx, utils.HashToken(dto.RefreshToken))
+	if err != nil {
+		return fmt.Errorf("invalid refresh token - Logout: %v", err)
+	}
+
+	if stored.UserID != userID {
+		return fmt.Errorf("invalid refresh token - Logout: token belongs to another user")
+	}
+	// other sessions are ended through the session endpoints
+	if stored.FamilyID != sessionID {
+		return fmt.Errorf("invalid refresh token - Logout: token belongs to another session")
+	}
+
+	if err := s.authRepo.RevokeFamily(ctx, stored.FamilyID); err != nil {
+		return fmt.Errorf("Logout: %v", err)
+	}
+
+	if err := s.sessions.Revoke(ctx, userID, stored.FamilyID); err != nil && err.Error() != "session not found" {
+		return fmt.Errorf("Logout: %v", err)
+	}
+
+	if err := utils.RevokeToken(ctx, s.rdb, tokenID, tokenExpiresAt); err != nil {
+		return fmt.Errorf("Logout: %v", err)
+	}
+
+	return nil
+}
+
+// ForgotPassword never reveals whether the email is registered; failures after
+// the lookup are only logged so the response is the same either way
+func (s *authService) ForgotPassword(ctx context.Context, dto *ForgotPasswordRequest) error {
+	userInfo, err := s.repo.FindByEmail(ctx, dto.Email)
+	if err != nil {
+		if err.Error() == "user not found" {
+			return nil
+		}
+		return fmt.Errorf("ForgotPassword: %v", err)
+	}
+
+	if err := s.sendPasswordReset(ctx, userInfo); err != nil {
+		log.Printf("failed to send password reset - ForgotPassword: %v", err)
+	}
+	return nil
+}
+
+// sendPasswordReset replaces the user's reset tokens with a new one and mails it
+func (s *authService) sendPasswordReset(ctx context.Context, userInfo *user.User) error {
+	if err := s.tokenRepo.InvalidateForUser(ctx, userInfo.ID, usertoken.PurposePasswordReset); err != nil {
+		return err
+	}
+
+	token, tokenHash, err := utils.NewOpaqueToken()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	ttl := time.Minute * time.Duration(s.config.Auth.PasswordResetTTLMinutes)
+	err = s.tokenRepo.Create(ctx, &usertoken.UserToken{
+		UserID:    userInfo.ID,
+		Purpose:   usertoken.PurposePasswordReset,
+		TokenHash: tokenHash,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, &mailer.Message{
+		To:      userInfo.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nSomeone asked to reset the password of your account. "+
+			"If it was you, send the token below to POST %s/auth/password/reset within %d minutes:\n\n%s\n\n"+
+			"If it wasn't you, you can ignore this email.\n",
+			userInfo.Name, s.config.Server.PublicURL, s.config.Auth.PasswordResetTTLMinutes, token),
+	})
+}
+
+func (s *authService) ResetPassword(ctx context.Context, dto *ResetPasswordRequest) error {
+	// checked before the token is consumed so a rejected password can be retried
+	if err := s.policy.Validate(dto.Password); err != nil {
+		return fmt.Errorf("ResetPassword: %w", err)
+	}
+
+	token, err := s.tokenRepo.FindValid(ctx, usertoken.PurposePasswordReset, utils.HashToken(dto.Token))
+	if err != nil {
+		return fmt.Errorf("ResetPassword: %v", err)
+	}
+
+	consumed, err := s.tokenRepo.MarkUsed(ctx, token.ID)
+	if err != nil {
+		return fmt.Errorf("ResetPassword: %v", err)
+	}
+	if !consumed {
+		return fmt.Errorf("ResetPassword: token invalid or expired")
+	}
+
+	pwHash, err := s.hasher.Hash(dto.Password)
+	if err != nil {
+		return fmt.Errorf("failed to generate password hash - ResetPassword: %v", err)
+	}
+
+	if err := s.repo.UpdatePassword(ctx, token.UserID, pwHash); err != nil {
+		return fmt.Errorf("ResetPassword: %v", err)
+	}
+
+	// whoever knew the old password should not stay logged in
+	if err := s.authRepo.RevokeAllForUser(ctx, token.UserID); err != nil {
+		return fmt.Errorf("ResetPassword: %v", err)
+	}
+	if err := s.sessions.RevokeAllExcept(ctx, token.UserID, uuid.Nil); err != nil {
+		return fmt.Errorf("ResetPassword: %v", err)
+	}
+
+	return nil
+}
+
+func (s *authService) sendVerification(ctx context.Context, userInfo *user.User) error {
+	if err := s.tokenRepo.InvalidateForUser(ctx, userInfo.ID, usertoken.PurposeEmailVerification); err != nil {
+		return fmt.Errorf("sendVerification: %v", err)
+	}
+
+	token, tokenHash, err := utils.NewOpaqueToken()
+	if err != nil {
+		return fmt.Errorf("sendVerification: %v", err)
+	}
+
+	now := time.Now()
+	err = s.tokenRepo.Create(ctx, &usertoken.UserToken{
+		UserID:    userInfo.ID,
+		Purpose:   usertoken.PurposeEmailVerification,
+		TokenHash: tokenHash,
+		ExpiresAt: now.Add(time.Hour * time.Duration(s.config.Auth.VerificationTTLHours)),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return fmt.Errorf("sendVerification: %v", err)
+	}
+
+	err = s.mailer.Send(ctx, &mailer.Message{
+		To:      userInfo.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Hi %s,\n\nPlease confirm your email address by opening the link below:\n\n"+
+			"%s/auth/verify?token=%s\n\nThe link expires in %d hours.\n",
+			userInfo.Name, s.config.Server.PublicURL, token, s.config.Auth.VerificationTTLHours),
+	})
+	if err != nil {
+		return fmt.Errorf("sendVerification: %v", err)
+	}
+
+	return nil
+}
+
+func (s *authService) VerifyEmail(ctx context.Context, token string) error {
+	stored, err := s.tokenRepo.FindValid(ctx, usertoken.PurposeEmailVerification, utils.HashToken(token))
+	if err != nil {
+		return fmt.Errorf("VerifyEmail: %v", err)
+	}
+
+	consumed, err := s.tokenRepo.MarkUsed(ctx, stored.ID)
+	if err != nil {
+		return fmt.Errorf("VerifyEmail: %v", err)
+	}
+	if !consumed {
+		return fmt.Errorf("VerifyEmail: token invalid or expired")
+	}
+
+	if err := s.repo.MarkEmailVerified(ctx, stored.UserID); err != nil {
+		return fmt.Errorf("VerifyEmail: %v", err)
+	}
+
+	return nil
+}
+
+func (s *authService) ResendVerification(ctx context.Context, userID uuid.UUID) error {
+	userInfo, err := s.repo.FindByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("ResendVerification: %v", err)
+	}
+
+	if userInfo.EmailVerifiedAt != nil {
+		return fmt.Errorf("email already verified")
+	}
+
+	if err := s.sendVerification(ctx, userInfo); err != nil {
+		return fmt.Errorf("ResendVerification: %v", err)
+	}
+
+	return nil
+}
 
+func (s *authService) JWKS() *utils.JWKS {
+	return s.keys.JWKS()
 }
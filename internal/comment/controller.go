@@ -0,0 +1,185 @@
+package comment
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type Controller struct {
+	service Service
+}
+
+func NewController(service Service) *Controller {
+	return &Controller{service: service}
+}
+
+// respondError maps service errors to status codes
+func respondError(c *gin.Context, err error) {
+	if strings.Contains(err.Error(), "not found") {
+		c.IndentedJSON(404, gin.H{"error": err.Error()})
+		return
+	}
+	if strings.Contains(err.Error(), "unauthorized") {
+		c.IndentedJSON(403, gin.H{"error": err.Error()})
+		return
+	}
+	if strings.Contains(err.Error(), "archived") {
+		c.IndentedJSON(409, gin.H{"error": err.Error()})
+		return
+	}
+	c.IndentedJSON(500, gin.H{"error": err.Error()})
+}
+
+// GET /tasks/:id/comments
+func (controller *Controller) ListForTask(c *gin.Context) {
+	controller.list(c, "task", controller.service.ListForTask)
+}
+
+// GET /subtasks/:id/comments
+func (controller *Controller) ListForSubtask(c *gin.Context) {
+	controller.list(c, "subtask", controller.service.ListForSubtask)
+}
+
+func (controller *Controller) list(c *gin.Context, kind string,
+	list func(context.Context, uuid.UUID, int64) ([]*ThreadResponse, error)) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.IndentedJSON(401, gin.H{"error": "unauthorized"})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.IndentedJSON(400, gin.H{"error": "invalid " + kind + " id"})
+		return
+	}
+
+	threads, err := list(c.Request.Context(), userUUID, id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.IndentedJSON(200, threads)
+}
+
+// POST /tasks/:id/comments
+func (controller *Controller) CreateForTask(c *gin.Context) {
+	controller.create(c, "task", controller.service.CreateForTask)
+}
+
+// POST /subtasks/:id/comments
+func (controller *Controller) CreateForSubtask(c *gin.Context) {
+	controller.create(c, "subtask", controller.service.CreateForSubtask)
+}
+
+func (controller *Controller) create(c *gin.Context, kind string,
+	create func(context.Context, uuid.UUID, int64, *CreateCommentRequest) (*CommentResponse, error)) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.IndentedJSON(401, gin.H{"error": "unauthorized"})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.IndentedJSON(400, gin.H{"error": "invalid " + kind + " id"})
+		return
+	}
+
+	var req CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.IndentedJSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	comment, err := create(c.Request.Context(), userUUID, id, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.IndentedJSON(201, comment)
+}
+
+func (controller *Controller) Update(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.IndentedJSON(401, gin.H{"error": "unauthorized"})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.IndentedJSON(400, gin.H{"error": "invalid comment id"})
+		return
+	}
+
+	var req UpdateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.IndentedJSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	comment, err := controller.service.Update(c.Request.Context(), userUUID, id, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.IndentedJSON(200, comment)
+}
+
+func (controller *Controller) Delete(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.IndentedJSON(401, gin.H{"error": "unauthorized"})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.IndentedJSON(400, gin.H{"error": "invalid comment id"})
+		return
+	}
+
+	if err := controller.service.Delete(c.Request.Context(), userUUID, id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.IndentedJSON(200, gin.H{"message": "comment deleted"})
+}
+
+// Edits lists the earlier versions of a comment
+// GET /comments/:id/edits
+func (controller *Controller) Edits(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.IndentedJSON(401, gin.H{"error": "unauthorized"})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.IndentedJSON(400, gin.H{"error": "invalid comment id"})
+		return
+	}
+
+	edits, err := controller.service.Edits(c.Request.Context(), userUUID, id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.IndentedJSON(200, edits)
+}
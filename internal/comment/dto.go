@@ -0,0 +1,93 @@
+package comment
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateCommentRequest struct {
+	Body string `json:"body" binding:"required,min=1,max=10000"`
+	// ParentID makes the comment a reply. Replies to a reply join the thread
+	// of the comment that was replied to.
+	ParentID *int64 `json:"parent_id" binding:"omitempty,min=1"`
+}
+
+type UpdateCommentRequest struct {
+	Body string `json:"body" binding:"required,min=1,max=10000"`
+}
+
+type CommentResponse struct {
+	ID        int64      `json:"id"`
+	TaskID    int64      `json:"task_id"`
+	SubtaskID *int64     `json:"subtask_id"`
+	ParentID  *int64     `json:"parent_id"`
+	AuthorID  *uuid.UUID `json:"author_id"`
+	Body      string     `json:"body"`
+	EditedAt  *time.Time `json:"edited_at"`
+	DeletedAt *time.Time `json:"deleted_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// ThreadResponse is a top-level comment with its replies, oldest first
+type ThreadResponse struct {
+	*CommentResponse
+	Replies []*CommentResponse `json:"replies"`
+}
+
+type CommentEditResponse struct {
+	Body     string    `json:"body"`
+	EditedAt time.Time `json:"edited_at"`
+}
+
+func ToCommentResponse(comment *Comment) *CommentResponse {
+	return &CommentResponse{
+		ID:        comment.ID,
+		TaskID:    comment.TaskID,
+		SubtaskID: comment.SubtaskID,
+		ParentID:  comment.ParentID,
+		AuthorID:  comment.AuthorID,
+		Body:      comment.Body,
+		EditedAt:  comment.EditedAt,
+		DeletedAt: comment.DeletedAt,
+		CreatedAt: comment.CreatedAt,
+	}
+}
+
+func ToCommentResponseList(comments []*Comment) []*CommentResponse {
+	responses := make([]*CommentResponse, len(comments))
+	for i, comment := range comments {
+		responses[i] = ToCommentResponse(comment)
+	}
+	return responses
+}
+
+// ToThreadResponseList groups comments, ordered oldest first, into threads
+func ToThreadResponseList(comments []*Comment) []*ThreadResponse {
+	threads := []*ThreadResponse{}
+	byID := map[int64]*ThreadResponse{}
+	for _, c := range comments {
+		if c.ParentID == nil {
+			thread := &ThreadResponse{CommentResponse: ToCommentResponse(c), Replies: []*CommentResponse{}}
+			byID[c.ID] = thread
+			threads = append(threads, thread)
+		}
+	}
+	for _, c := range comments {
+		if c.ParentID == nil {
+			continue
+		}
+		if thread, ok := byID[*c.ParentID]; ok {
+			thread.Replies = append(thread.Replies, ToCommentResponse(c))
+		}
+	}
+	return threads
+}
+
+func ToCommentEditResponseList(edits []*CommentEdit) []*CommentEditResponse {
+	responses := make([]*CommentEditResponse, len(edits))
+	for i, edit := range edits {
+		responses[i] = &CommentEditResponse{Body: edit.Body, EditedAt: edit.EditedAt}
+	}
+	return responses
+}
@@ -0,0 +1,41 @@
+package comment
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Comment is a comment on a task, or on one of its subtasks when SubtaskID
+// is set. Replies point at the top-level comment of their thread.
+type Comment struct {
+	ID        int64 `gorm:"primaryKey;autoIncrement"`
+	TaskID    int64 `gorm:"not null"`
+	SubtaskID *int64
+	ParentID  *int64
+	// AuthorID is cleared when the author deletes their account
+	AuthorID  *uuid.UUID `gorm:"type:uuid"`
+	Body      string     `gorm:"type:text;not null"`
+	EditedAt  *time.Time `gorm:"type:timestamp"`
+	CreatedAt time.Time  `gorm:"type:timestamp;not null"`
+	UpdatedAt time.Time  `gorm:"type:timestamp;not null"`
+	// DeletedAt marks a deleted comment kept with an empty body because it
+	// still has replies
+	DeletedAt *time.Time `gorm:"type:timestamp"`
+}
+
+func (Comment) TableName() string {
+	return "comment"
+}
+
+// CommentEdit keeps the body a comment had before an edit
+type CommentEdit struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement"`
+	CommentID int64     `gorm:"not null"`
+	Body      string    `gorm:"type:text;not null"`
+	EditedAt  time.Time `gorm:"type:timestamp;not null"`
+}
+
+func (CommentEdit) TableName() string {
+	return "comment_edit"
+}
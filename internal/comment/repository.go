@@ -0,0 +1,121 @@
+package comment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) Create(ctx context.Context, comment *Comment) error {
+	err := r.db.WithContext(ctx).Create(comment).Error
+	if err != nil {
+		return fmt.Errorf("comment - Create: %v", err)
+	}
+	return nil
+}
+
+func (r *Repository) FindByID(ctx context.Context, id int64) (*Comment, error) {
+	var comment Comment
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&comment).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("comment not found")
+		}
+		return nil, fmt.Errorf("FindByID: %v", err)
+	}
+	return &comment, nil
+}
+
+// FindByTarget lists the comments on a task, or on one of its subtasks when
+// subtaskID is set, oldest first
+func (r *Repository) FindByTarget(ctx context.Context, taskID int64, subtaskID *int64) ([]*Comment, error) {
+	var comments []*Comment
+	query := r.db.WithContext(ctx).Where("task_id = ?", taskID)
+	if subtaskID != nil {
+		query = query.Where("subtask_id = ?", *subtaskID)
+	} else {
+		query = query.Where("subtask_id IS NULL")
+	}
+
+	err := query.Order("id").Find(&comments).Error
+	if err != nil {
+		return nil, fmt.Errorf("FindByTarget: %v", err)
+	}
+	return comments, nil
+}
+
+// Update saves the comment and keeps previousBody in its edit history
+func (r *Repository) Update(ctx context.Context, comment *Comment, previousBody string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		edit := &CommentEdit{CommentID: comment.ID, Body: previousBody, EditedAt: *comment.EditedAt}
+		if err := tx.Create(edit).Error; err != nil {
+			return err
+		}
+		return tx.Save(comment).Error
+	})
+	if err != nil {
+		return fmt.Errorf("comment - Update: %v", err)
+	}
+	return nil
+}
+
+// Delete removes the comment with its edit history. A top-level comment
+// that has replies is kept as a tombstone instead: its body, history and
+// mentions are cleared so the replies keep their thread. Deleting the last
+// reply under a tombstone removes the tombstone too.
+func (r *Repository) Delete(ctx context.Context, comment *Comment) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var replies int64
+		if err := tx.Model(&Comment{}).Where("parent_id = ?", comment.ID).Count(&replies).Error; err != nil {
+			return err
+		}
+
+		if replies == 0 {
+			if err := tx.Delete(&Comment{}, comment.ID).Error; err != nil {
+				return err
+			}
+			if comment.ParentID == nil {
+				return nil
+			}
+			// a tombstone is only kept while it has replies
+			return tx.Where("id = ? AND deleted_at IS NOT NULL", *comment.ParentID).
+				Where("NOT EXISTS (SELECT 1 FROM comment reply WHERE reply.parent_id = comment.id)").
+				Delete(&Comment{}).Error
+		}
+
+		if err := tx.Where("comment_id = ?", comment.ID).Delete(&CommentEdit{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM mention WHERE comment_id = ?", comment.ID).Error; err != nil {
+			return err
+		}
+		now := time.Now()
+		return tx.Model(&Comment{}).Where("id = ?", comment.ID).
+			Updates(map[string]interface{}{"body": "", "edited_at": nil, "deleted_at": now, "updated_at": now}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("comment - Delete: %v", err)
+	}
+	return nil
+}
+
+// Edits lists the earlier versions of a comment, newest first
+func (r *Repository) Edits(ctx context.Context, commentID int64) ([]*CommentEdit, error) {
+	var edits []*CommentEdit
+	err := r.db.WithContext(ctx).Where("comment_id = ?", commentID).Order("id DESC").Find(&edits).Error
+	if err != nil {
+		return nil, fmt.Errorf("Edits: %v", err)
+	}
+	return edits, nil
+}
@@ -0,0 +1,22 @@
+package comment
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRoutes(group *gin.RouterGroup, controller *Controller,
+	authMw gin.HandlerFunc, scopeMw gin.HandlerFunc, rateLimitMw gin.HandlerFunc) {
+	comments := group.Group("")
+	comments.Use(authMw)
+	comments.Use(scopeMw)
+	comments.Use(rateLimitMw)
+	{
+		comments.GET("/tasks/:id/comments", controller.ListForTask)
+		comments.POST("/tasks/:id/comments", controller.CreateForTask)
+		comments.GET("/subtasks/:id/comments", controller.ListForSubtask)
+		comments.POST("/subtasks/:id/comments", controller.CreateForSubtask)
+		comments.PUT("/comments/:id", controller.Update)
+		comments.DELETE("/comments/:id", controller.Delete)
+		comments.GET("/comments/:id/edits", controller.Edits)
+	}
+}
@@ -0,0 +1,303 @@
+package comment
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"task-management/internal/mention"
+	"task-management/internal/project"
+	"task-management/internal/subtask"
+	"task-management/internal/task"
+
+	"github.com/google/uuid"
+)
+
+type Service interface {
+	ListForTask(ctx context.Context, userID uuid.UUID, taskID int64) ([]*ThreadResponse, error)
+	ListForSubtask(ctx context.Context, userID uuid.UUID, subtaskID int64) ([]*ThreadResponse, error)
+	CreateForTask(ctx context.Context, userID uuid.UUID, taskID int64, dto *CreateCommentRequest) (*CommentResponse, error)
+	CreateForSubtask(ctx context.Context, userID uuid.UUID, subtaskID int64, dto *CreateCommentRequest) (*CommentResponse, error)
+	// Update changes the body of the user's own comment
+	Update(ctx context.Context, userID uuid.UUID, id int64, dto *UpdateCommentRequest) (*CommentResponse, error)
+	// Delete removes a comment of the user's, or anyone's for project admins.
+	// A top-level comment with replies is left behind empty.
+	Delete(ctx context.Context, userID uuid.UUID, id int64) error
+	Edits(ctx context.Context, userID uuid.UUID, id int64) ([]*CommentEditResponse, error)
+}
+
+type service struct {
+	repo        *Repository
+	taskRepo    *task.Repository
+	subtaskRepo *subtask.Repository
+	projectRepo *project.Repository
+	mentions    mention.Service
+}
+
+func NewService(repo *Repository, taskRepo *task.Repository, subtaskRepo *subtask.Repository, projectRepo *project.Repository,
+	mentions mention.Service) Service {
+	return &service{
+		repo:        repo,
+		taskRepo:    taskRepo,
+		subtaskRepo: subtaskRepo,
+		projectRepo: projectRepo,
+		mentions:    mentions,
+	}
+}
+
+// target is what a comment is attached to
+type target struct {
+	taskID    int64
+	subtaskID *int64
+	projectID int64
+}
+
+func (s *service) taskTarget(ctx context.Context, taskID int64) (*target, error) {
+	t, err := s.taskRepo.FindByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	return &target{taskID: t.ID, projectID: t.ProjectID}, nil
+}
+
+func (s *service) subtaskTarget(ctx context.Context, subtaskID int64) (*target, error) {
+	st, err := s.subtaskRepo.FindByID(ctx, subtaskID)
+	if err != nil {
+		return nil, err
+	}
+	t, err := s.taskRepo.FindByID(ctx, st.TaskID)
+	if err != nil {
+		return nil, err
+	}
+	return &target{taskID: t.ID, subtaskID: &st.ID, projectID: t.ProjectID}, nil
+}
+
+// targetOf finds what a comment is on; comments on trashed work are not found
+func (s *service) targetOf(ctx context.Context, comment *Comment) (*target, error) {
+	if comment.SubtaskID != nil {
+		return s.subtaskTarget(ctx, *comment.SubtaskID)
+	}
+	return s.taskTarget(ctx, comment.TaskID)
+}
+
+// syncMentions records who a saved comment mentions and has them watch the
+// task, logging failures rather than reporting the comment as not saved
+func (s *service) syncMentions(ctx context.Context, userID uuid.UUID, t *target, comment *Comment) {
+	source := mention.Source{ProjectID: t.projectID, TaskID: t.taskID, SubtaskID: t.subtaskID, CommentID: &comment.ID}
+	mentioned, err := s.mentions.Sync(ctx, userID, source, &comment.Body)
+	if err != nil {
+		log.Printf("comment %d: %v", comment.ID, err)
+	}
+	if len(mentioned) > 0 {
+		if err := s.taskRepo.AddWatchers(ctx, t.taskID, mentioned); err != nil {
+			log.Printf("comment %d: %v", comment.ID, err)
+		}
+	}
+}
+
+func isAuthor(comment *Comment, userID uuid.UUID) bool {
+	return comment.AuthorID != nil && *comment.AuthorID == userID
+}
+
+// authorizeAuthor checks that an author may still change their own comment.
+// That only takes access to the project, so someone demoted to viewer can
+// still edit or delete what they wrote, but not in an archived project.
+func (s *service) authorizeAuthor(ctx context.Context, userID uuid.UUID, t *target) error {
+	p, _, err := s.projectRepo.Authorize(ctx, t.projectID, userID, project.ActionView)
+	if err != nil {
+		return err
+	}
+	if p.ArchivedAt != nil {
+		return fmt.Errorf("project is archived and read-only")
+	}
+	return nil
+}
+
+func (s *service) list(ctx context.Context, userID uuid.UUID, t *target) ([]*ThreadResponse, error) {
+	if _, _, err := s.projectRepo.Authorize(ctx, t.projectID, userID, project.ActionView); err != nil {
+		return nil, err
+	}
+
+	comments, err := s.repo.FindByTarget(ctx, t.taskID, t.subtaskID)
+	if err != nil {
+		return nil, err
+	}
+	return ToThreadResponseList(comments), nil
+}
+
+func (s *service) ListForTask(ctx context.Context, userID uuid.UUID, taskID int64) ([]*ThreadResponse, error) {
+	t, err := s.taskTarget(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("ListForTask: %v", err)
+	}
+	threads, err := s.list(ctx, userID, t)
+	if err != nil {
+		return nil, fmt.Errorf("ListForTask: %v", err)
+	}
+	return threads, nil
+}
+
+func (s *service) ListForSubtask(ctx context.Context, userID uuid.UUID, subtaskID int64) ([]*ThreadResponse, error) {
+	t, err := s.subtaskTarget(ctx, subtaskID)
+	if err != nil {
+		return nil, fmt.Errorf("ListForSubtask: %v", err)
+	}
+	threads, err := s.list(ctx, userID, t)
+	if err != nil {
+		return nil, fmt.Errorf("ListForSubtask: %v", err)
+	}
+	return threads, nil
+}
+
+func (s *service) create(ctx context.Context, userID uuid.UUID, t *target, dto *CreateCommentRequest) (*CommentResponse, error) {
+	if _, _, err := s.projectRepo.Authorize(ctx, t.projectID, userID, project.ActionComment); err != nil {
+		return nil, err
+	}
+
+	var parentID *int64
+	if dto.ParentID != nil {
+		parent, err := s.repo.FindByID(ctx, *dto.ParentID)
+		if err != nil {
+			return nil, fmt.Errorf("parent %v", err)
+		}
+		if parent.TaskID != t.taskID || !sameSubtask(parent.SubtaskID, t.subtaskID) {
+			return nil, fmt.Errorf("parent comment not found")
+		}
+		// threads are one level deep
+		parentID = &parent.ID
+		if parent.ParentID != nil {
+			parentID = parent.ParentID
+		}
+	}
+
+	now := time.Now()
+	comment := &Comment{
+		TaskID:    t.taskID,
+		SubtaskID: t.subtaskID,
+		ParentID:  parentID,
+		AuthorID:  &userID,
+		Body:      dto.Body,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.repo.Create(ctx, comment); err != nil {
+		return nil, err
+	}
+	if err := s.taskRepo.AddWatchers(ctx, t.taskID, []uuid.UUID{userID}); err != nil {
+		log.Printf("comment %d: %v", comment.ID, err)
+	}
+	s.syncMentions(ctx, userID, t, comment)
+	return ToCommentResponse(comment), nil
+}
+
+func sameSubtask(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func (s *service) CreateForTask(ctx context.Context, userID uuid.UUID, taskID int64, dto *CreateCommentRequest) (*CommentResponse, error) {
+	t, err := s.taskTarget(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("CreateForTask: %v", err)
+	}
+	comment, err := s.create(ctx, userID, t, dto)
+	if err != nil {
+		return nil, fmt.Errorf("CreateForTask: %v", err)
+	}
+	return comment, nil
+}
+
+func (s *service) CreateForSubtask(ctx context.Context, userID uuid.UUID, subtaskID int64, dto *CreateCommentRequest) (*CommentResponse, error) {
+	t, err := s.subtaskTarget(ctx, subtaskID)
+	if err != nil {
+		return nil, fmt.Errorf("CreateForSubtask: %v", err)
+	}
+	comment, err := s.create(ctx, userID, t, dto)
+	if err != nil {
+		return nil, fmt.Errorf("CreateForSubtask: %v", err)
+	}
+	return comment, nil
+}
+
+func (s *service) Update(ctx context.Context, userID uuid.UUID, id int64, dto *UpdateCommentRequest) (*CommentResponse, error) {
+	comment, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("Update: %v", err)
+	}
+	if comment.DeletedAt != nil {
+		return nil, fmt.Errorf("Update: comment not found")
+	}
+	t, err := s.targetOf(ctx, comment)
+	if err != nil {
+		return nil, fmt.Errorf("Update: %v", err)
+	}
+	if !isAuthor(comment, userID) {
+		return nil, fmt.Errorf("unauthorized: only the author can edit a comment")
+	}
+	if err := s.authorizeAuthor(ctx, userID, t); err != nil {
+		return nil, fmt.Errorf("Update: %v", err)
+	}
+
+	if comment.Body == dto.Body {
+		return ToCommentResponse(comment), nil
+	}
+
+	now := time.Now()
+	previous := comment.Body
+	comment.Body = dto.Body
+	comment.EditedAt = &now
+	comment.UpdatedAt = now
+	if err := s.repo.Update(ctx, comment, previous); err != nil {
+		return nil, fmt.Errorf("Update: %v", err)
+	}
+	s.syncMentions(ctx, userID, t, comment)
+	return ToCommentResponse(comment), nil
+}
+
+func (s *service) Delete(ctx context.Context, userID uuid.UUID, id int64) error {
+	comment, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("Delete: %v", err)
+	}
+	if comment.DeletedAt != nil {
+		return fmt.Errorf("Delete: comment not found")
+	}
+	t, err := s.targetOf(ctx, comment)
+	if err != nil {
+		return fmt.Errorf("Delete: %v", err)
+	}
+
+	if isAuthor(comment, userID) {
+		err = s.authorizeAuthor(ctx, userID, t)
+	} else {
+		_, _, err = s.projectRepo.Authorize(ctx, t.projectID, userID, project.ActionModerateComments)
+	}
+	if err != nil {
+		return fmt.Errorf("Delete: %v", err)
+	}
+
+	return s.repo.Delete(ctx, comment)
+}
+
+func (s *service) Edits(ctx context.Context, userID uuid.UUID, id int64) ([]*CommentEditResponse, error) {
+	comment, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("Edits: %v", err)
+	}
+	t, err := s.targetOf(ctx, comment)
+	if err != nil {
+		return nil, fmt.Errorf("Edits: %v", err)
+	}
+	if _, _, err := s.projectRepo.Authorize(ctx, t.projectID, userID, project.ActionView); err != nil {
+		return nil, fmt.Errorf("Edits: %v", err)
+	}
+
+	edits, err := s.repo.Edits(ctx, comment.ID)
+	if err != nil {
+		return nil, fmt.Errorf("Edits: %v", err)
+	}
+	return ToCommentEditResponseList(edits), nil
+}
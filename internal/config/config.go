@@ -5,21 +5,31 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	JWT      JWTConfig
-	CORS     CORSConfig
-	Redis    RedisConfig
+	Server       ServerConfig
+	Database     DatabaseConfig
+	JWT          JWTConfig
+	CORS         CORSConfig
+	Redis        RedisConfig
+	Mail         MailConfig
+	Auth         AuthConfig
+	Security     SecurityConfig
+	OIDC         OIDCConfig
+	Password     PasswordConfig
+	Project      ProjectConfig
+	Trash        TrashConfig
+	Notification NotificationConfig
 }
 
 type ServerConfig struct {
-	Port string
-	Env  string
+	Port      string
+	Env       string
+	PublicURL string
 }
 
 type DatabaseConfig struct {
@@ -31,8 +41,16 @@ type DatabaseConfig struct {
 }
 
 type JWTConfig struct {
-	Secret      string
-	ExpireHours int
+	Secret              string
+	AccessExpireMinutes int
+	RefreshExpireHours  int
+	// KeysDir switches signing from the HS256 secret to the RSA/Ed25519
+	// keys in that directory: <kid>.pem for private keys, <kid>.pub.pem
+	// for retired keys that are only still accepted for verification
+	KeysDir   string
+	ActiveKID string
+	Issuer    string
+	Audience  string
 }
 
 type CORSConfig struct {
@@ -46,13 +64,86 @@ type RedisConfig struct {
 	DBName   int
 }
 
+type MailConfig struct {
+	Driver     string
+	Host       string
+	Port       string
+	Username   string
+	Password   string
+	From       string
+	OutboxPath string
+}
+
+type AuthConfig struct {
+	PasswordResetTTLMinutes int
+	VerificationTTLHours    int
+	// RestrictUnverified keeps accounts without a verified email out of
+	// collaborative features such as being added to projects
+	RestrictUnverified  bool
+	TOTPIssuer          string
+	ChallengeTTLMinutes int
+}
+
+type SecurityConfig struct {
+	MaxLoginAttempts     int
+	FailureWindowMinutes int
+	LockoutBaseSeconds   int
+	LockoutMaxMinutes    int
+}
+
+type PasswordConfig struct {
+	// HashAlgorithm is used for new hashes; hashes made with the other
+	// algorithm or older parameters are replaced on the next login
+	HashAlgorithm     string
+	Argon2MemoryKiB   int
+	Argon2Iterations  int
+	Argon2Parallelism int
+	BcryptCost        int
+	MinLength         int
+	DenyCommon        bool
+}
+
+type ProjectConfig struct {
+	InvitationTTLHours int
+	// TransferPreviousOwnerRole is the role a previous owner keeps after
+	// handing the project over, unless the transfer request names another
+	TransferPreviousOwnerRole string
+}
+
+type TrashConfig struct {
+	// RetentionDays is how long deleted items can be restored before they
+	// are purged for good
+	RetentionDays        int
+	PurgeIntervalMinutes int
+}
+
+type NotificationConfig struct {
+	// DueSoonHours is how long before its due date an assignee hears that
+	// a task or subtask is due
+	DueSoonHours           int
+	DueSoonIntervalMinutes int
+}
+
+type OIDCConfig struct {
+	Providers map[string]OIDCProviderConfig
+}
+
+type OIDCProviderConfig struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
 func Load() (*Config, error) {
 	_ = godotenv.Load()
 
 	config := &Config{
 		Server: ServerConfig{
-			Port: getEnvVal("PORT", "8080"),
-			Env:  getEnvVal("ENV", "development"),
+			Port:      getEnvVal("PORT", "8080"),
+			Env:       getEnvVal("ENV", "development"),
+			PublicURL: getEnvVal("PUBLIC_URL", "http://localhost:8080"),
 		},
 		Database: DatabaseConfig{
 			Host:     getEnvVal("DB_HOST", "localhost"),
@@ -62,8 +153,13 @@ func Load() (*Config, error) {
 			DBName:   getEnvVal("DB_NAME", "task_management"),
 		},
 		JWT: JWTConfig{
-			Secret:      getEnvVal("JWT_SECRET", ""),
-			ExpireHours: getEnvIntVal("JWT_EXPIRE_HOURS", 24),
+			Secret:              getEnvVal("JWT_SECRET", ""),
+			AccessExpireMinutes: getEnvIntVal("JWT_ACCESS_EXPIRE_MINUTES", 15),
+			RefreshExpireHours:  getEnvIntVal("JWT_REFRESH_EXPIRE_HOURS", 720),
+			KeysDir:             getEnvVal("JWT_KEYS_DIR", ""),
+			ActiveKID:           getEnvVal("JWT_ACTIVE_KID", ""),
+			Issuer:              getEnvVal("JWT_ISSUER", "task-management"),
+			Audience:            getEnvVal("JWT_AUDIENCE", "task-management-api"),
 		},
 		CORS: CORSConfig{
 			Origin: getEnvVal("CORS_ORIGIN", "*"),
@@ -74,16 +170,84 @@ func Load() (*Config, error) {
 			Password: getEnvVal("REDIS_PASSWORD", ""),
 			DBName:   getEnvIntVal("REDIS_DB_NAME", 0),
 		},
+		Mail: MailConfig{
+			Driver:     getEnvVal("MAIL_DRIVER", "outbox"),
+			Host:       getEnvVal("SMTP_HOST", "localhost"),
+			Port:       getEnvVal("SMTP_PORT", "587"),
+			Username:   getEnvVal("SMTP_USERNAME", ""),
+			Password:   getEnvVal("SMTP_PASSWORD", ""),
+			From:       getEnvVal("MAIL_FROM", "no-reply@task-management.local"),
+			OutboxPath: getEnvVal("MAIL_OUTBOX_PATH", "mail_outbox.log"),
+		},
+		Auth: AuthConfig{
+			PasswordResetTTLMinutes: getEnvIntVal("PASSWORD_RESET_TTL_MINUTES", 30),
+			VerificationTTLHours:    getEnvIntVal("EMAIL_VERIFICATION_TTL_HOURS", 48),
+			RestrictUnverified:      getEnvBoolVal("AUTH_RESTRICT_UNVERIFIED", true),
+			TOTPIssuer:              getEnvVal("TOTP_ISSUER", "TaskManagement"),
+			ChallengeTTLMinutes:     getEnvIntVal("TWO_FACTOR_CHALLENGE_TTL_MINUTES", 5),
+		},
+		Security: SecurityConfig{
+			MaxLoginAttempts:     getEnvIntVal("LOGIN_MAX_ATTEMPTS", 5),
+			FailureWindowMinutes: getEnvIntVal("LOGIN_FAILURE_WINDOW_MINUTES", 15),
+			LockoutBaseSeconds:   getEnvIntVal("LOGIN_LOCKOUT_BASE_SECONDS", 60),
+			LockoutMaxMinutes:    getEnvIntVal("LOGIN_LOCKOUT_MAX_MINUTES", 60),
+		},
+		Password: PasswordConfig{
+			HashAlgorithm:     getEnvVal("PASSWORD_HASH_ALGORITHM", "argon2id"),
+			Argon2MemoryKiB:   getEnvIntVal("ARGON2_MEMORY_KIB", 64*1024),
+			Argon2Iterations:  getEnvIntVal("ARGON2_ITERATIONS", 3),
+			Argon2Parallelism: getEnvIntVal("ARGON2_PARALLELISM", 2),
+			BcryptCost:        getEnvIntVal("BCRYPT_COST", 12),
+			MinLength:         getEnvIntVal("PASSWORD_MIN_LENGTH", 10),
+			DenyCommon:        getEnvBoolVal("PASSWORD_DENY_COMMON", true),
+		},
+		Project: ProjectConfig{
+			InvitationTTLHours:        getEnvIntVal("PROJECT_INVITATION_TTL_HOURS", 168),
+			TransferPreviousOwnerRole: getEnvVal("PROJECT_TRANSFER_PREVIOUS_OWNER_ROLE", "admin"),
+		},
+		Trash: TrashConfig{
+			RetentionDays:        getEnvIntVal("TRASH_RETENTION_DAYS", 30),
+			PurgeIntervalMinutes: getEnvIntVal("TRASH_PURGE_INTERVAL_MINUTES", 60),
+		},
+		Notification: NotificationConfig{
+			DueSoonHours:           getEnvIntVal("NOTIFICATION_DUE_SOON_HOURS", 24),
+			DueSoonIntervalMinutes: getEnvIntVal("NOTIFICATION_DUE_SOON_INTERVAL_MINUTES", 15),
+		},
 	}
+	config.OIDC = loadOIDCConfig(config.Server.PublicURL)
 	// fmt.Println(config.Database.Password, config.JWT.Secret)
 
-	if config.Database.Password == "" || config.JWT.Secret == "" {
+	if config.Database.Password == "" || (config.JWT.Secret == "" && config.JWT.KeysDir == "") {
 		return nil, fmt.Errorf("Load - unset confidential info")
 	}
 
 	return config, nil
 }
 
+// loadOIDCConfig reads OIDC_PROVIDERS=google,okta and then OIDC_GOOGLE_ISSUER,
+// OIDC_GOOGLE_CLIENT_ID, ... for every listed provider
+func loadOIDCConfig(publicURL string) OIDCConfig {
+	providers := map[string]OIDCProviderConfig{}
+
+	for _, name := range strings.Split(getEnvVal("OIDC_PROVIDERS", ""), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+
+		providers[name] = OIDCProviderConfig{
+			Issuer:       strings.TrimSuffix(getEnvVal(prefix+"ISSUER", ""), "/"),
+			ClientID:     getEnvVal(prefix+"CLIENT_ID", ""),
+			ClientSecret: getEnvVal(prefix+"CLIENT_SECRET", ""),
+			RedirectURL:  getEnvVal(prefix+"REDIRECT_URL", publicURL+"/auth/oidc/"+name+"/callback"),
+			Scopes:       strings.Fields(getEnvVal(prefix+"SCOPES", "openid email profile")),
+		}
+	}
+
+	return OIDCConfig{Providers: providers}
+}
+
 func getEnvVal(key, defaultVal string) string {
 	val := os.Getenv(key)
 	// fmt.Println(key, val)
@@ -104,3 +268,16 @@ func getEnvIntVal(key string, defaultVal int) int {
 	}
 	return res
 }
+
+func getEnvBoolVal(key string, defaultVal bool) bool {
+	val := os.Getenv(key)
+	if val == "" {
+		return defaultVal
+	}
+	res, err := strconv.ParseBool(val)
+	if err != nil {
+		log.Printf("value %s is not a valid boolean, switching to default value", val)
+		return defaultVal
+	}
+	return res
+}
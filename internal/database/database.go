@@ -58,7 +58,7 @@ func PostgresConnect(config *config.Config) (*gorm.DB, error) {
 
 func RedisConnect(config *config.Config) (*redis.Client, error) {
 	rdb := redis.NewClient(&redis.Options{
-		Addr:     config.Redis.Host + ":" + config.Server.Port,
+		Addr:     config.Redis.Host + ":" + config.Redis.Port,
 		Password: config.Redis.Password,
 		DB:       config.Redis.DBName,
 	})
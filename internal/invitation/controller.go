@@ -0,0 +1,199 @@
+package invitation
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type Controller struct {
+	service Service
+}
+
+func NewController(service Service) *Controller {
+	return &Controller{service: service}
+}
+
+// respondError maps the errors shared by every invitation endpoint
+func respondError(c *gin.Context, err error) {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "not found"):
+		c.IndentedJSON(404, gin.H{"error": msg})
+	case strings.Contains(msg, "unauthorized"):
+		c.IndentedJSON(403, gin.H{"error": msg})
+	case strings.Contains(msg, "email not verified"):
+		c.IndentedJSON(422, gin.H{"error": msg})
+	case strings.Contains(msg, "already pending"), strings.Contains(msg, "member already added"),
+		strings.Contains(msg, "no longer pending"):
+		c.IndentedJSON(409, gin.H{"error": msg})
+	default:
+		c.IndentedJSON(500, gin.H{"error": msg})
+	}
+}
+
+func (controller *Controller) Create(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.IndentedJSON(401, gin.H{"error": "unauthorized"})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+
+	projectID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.IndentedJSON(400, gin.H{"error": "invalid project id"})
+		return
+	}
+
+	var dto CreateInvitationRequest
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.IndentedJSON(400, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	invitation, err := controller.service.Create(c.Request.Context(), projectID, userUUID, &dto)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.IndentedJSON(201, invitation)
+}
+
+func (controller *Controller) ListForProject(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.IndentedJSON(401, gin.H{"error": "unauthorized"})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+
+	projectID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.IndentedJSON(400, gin.H{"error": "invalid project id"})
+		return
+	}
+
+	invitations, err := controller.service.ListForProject(c.Request.Context(), projectID, userUUID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.IndentedJSON(200, invitations)
+}
+
+func (controller *Controller) Revoke(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.IndentedJSON(401, gin.H{"error": "unauthorized"})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+
+	projectID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.IndentedJSON(400, gin.H{"error": "invalid project id"})
+		return
+	}
+
+	invitationID, err := strconv.ParseInt(c.Param("invitationId"), 10, 64)
+	if err != nil {
+		c.IndentedJSON(400, gin.H{"error": "invalid invitation id"})
+		return
+	}
+
+	if err := controller.service.Revoke(c.Request.Context(), projectID, userUUID, invitationID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.IndentedJSON(200, gin.H{"message": "invitation revoked"})
+}
+
+func (controller *Controller) ListMine(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.IndentedJSON(401, gin.H{"error": "unauthorized"})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+
+	invitations, err := controller.service.ListMine(c.Request.Context(), userUUID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.IndentedJSON(200, invitations)
+}
+
+func (controller *Controller) Accept(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.IndentedJSON(401, gin.H{"error": "unauthorized"})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+
+	invitationID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.IndentedJSON(400, gin.H{"error": "invalid invitation id"})
+		return
+	}
+
+	if err := controller.service.Accept(c.Request.Context(), userUUID, invitationID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.IndentedJSON(200, gin.H{"message": "invitation accepted"})
+}
+
+func (controller *Controller) Decline(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.IndentedJSON(401, gin.H{"error": "unauthorized"})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+
+	invitationID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.IndentedJSON(400, gin.H{"error": "invalid invitation id"})
+		return
+	}
+
+	if err := controller.service.Decline(c.Request.Context(), userUUID, invitationID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.IndentedJSON(200, gin.H{"message": "invitation declined"})
+}
+
+func (controller *Controller) AcceptByToken(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.IndentedJSON(401, gin.H{"error": "unauthorized"})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+
+	var dto AcceptTokenRequest
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.IndentedJSON(400, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	invitation, err := controller.service.AcceptByToken(c.Request.Context(), userUUID, dto.Token)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.IndentedJSON(200, invitation)
+}
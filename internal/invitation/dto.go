@@ -0,0 +1,50 @@
+package invitation
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateInvitationRequest struct {
+	Email string `json:"email" binding:"required,email,max=255"`
+	// Role defaults to editor
+	Role string `json:"role" binding:"omitempty,oneof=viewer editor maintainer admin"`
+}
+
+type AcceptTokenRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type InvitationResponse struct {
+	ID          int64     `json:"id"`
+	ProjectID   int64     `json:"project_id"`
+	ProjectName string    `json:"project_name,omitempty"`
+	InviterID   uuid.UUID `json:"inviter_id"`
+	Email       string    `json:"email"`
+	Role        string    `json:"role"`
+	Status      string    `json:"status"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func ToInvitationResponse(invitation *Invitation) *InvitationResponse {
+	return &InvitationResponse{
+		ID:        invitation.ID,
+		ProjectID: invitation.ProjectID,
+		InviterID: invitation.InviterID,
+		Email:     invitation.Email,
+		Role:      invitation.Role,
+		Status:    invitation.Status(time.Now()),
+		ExpiresAt: invitation.ExpiresAt,
+		CreatedAt: invitation.CreatedAt,
+	}
+}
+
+func ToInvitationResponseList(invitations []*Invitation) []*InvitationResponse {
+	responses := make([]*InvitationResponse, len(invitations))
+	for i, invitation := range invitations {
+		responses[i] = ToInvitationResponse(invitation)
+	}
+	return responses
+}
@@ -0,0 +1,48 @@
+package invitation
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Invitation statuses, derived from the timestamps
+const (
+	StatusPending  = "pending"
+	StatusAccepted = "accepted"
+	StatusDeclined = "declined"
+	StatusRevoked  = "revoked"
+	StatusExpired  = "expired"
+)
+
+type Invitation struct {
+	ID         int64      `gorm:"primaryKey;autoIncrement"`
+	ProjectID  int64      `gorm:"not null;index"`
+	InviterID  uuid.UUID  `gorm:"type:uuid;not null"`
+	Email      string     `gorm:"type:varchar(255);not null"`
+	Role       string     `gorm:"type:varchar(20);not null"`
+	TokenHash  string     `gorm:"type:varchar(64);not null;uniqueIndex"`
+	ExpiresAt  time.Time  `gorm:"type:timestamp;not null"`
+	AcceptedAt *time.Time `gorm:"type:timestamp"`
+	DeclinedAt *time.Time `gorm:"type:timestamp"`
+	RevokedAt  *time.Time `gorm:"type:timestamp"`
+	CreatedAt  time.Time  `gorm:"type:timestamp;not null"`
+}
+
+func (Invitation) TableName() string {
+	return "project_invitation"
+}
+
+func (i *Invitation) Status(now time.Time) string {
+	switch {
+	case i.AcceptedAt != nil:
+		return StatusAccepted
+	case i.DeclinedAt != nil:
+		return StatusDeclined
+	case i.RevokedAt != nil:
+		return StatusRevoked
+	case !now.Before(i.ExpiresAt):
+		return StatusExpired
+	}
+	return StatusPending
+}
@@ -0,0 +1,159 @@
+package invitation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"task-management/internal/project"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const pendingCondition = "accepted_at IS NULL AND declined_at IS NULL AND revoked_at IS NULL AND expires_at > ?"
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{db: db}
+}
+
+// invitationWithProject is a pending invitation as shown to the invitee
+type invitationWithProject struct {
+	Invitation  `gorm:"embedded"`
+	ProjectName string
+}
+
+func (r *Repository) Create(ctx context.Context, invitation *Invitation) error {
+	err := r.db.WithContext(ctx).Create(invitation).Error
+	if err != nil {
+		return fmt.Errorf("invitation - Create: %v", err)
+	}
+	return nil
+}
+
+func (r *Repository) FindByID(ctx context.Context, id int64) (*Invitation, error) {
+	var invitation Invitation
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&invitation).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("invitation not found")
+		}
+		return nil, fmt.Errorf("FindByID: %v", err)
+	}
+	return &invitation, nil
+}
+
+func (r *Repository) FindPendingByToken(ctx context.Context, tokenHash string) (*Invitation, error) {
+	var invitation Invitation
+	err := r.db.WithContext(ctx).Where("token_hash = ? AND "+pendingCondition, tokenHash, time.Now()).First(&invitation).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("invitation not found")
+		}
+		return nil, fmt.Errorf("FindPendingByToken: %v", err)
+	}
+	return &invitation, nil
+}
+
+func (r *Repository) HasPending(ctx context.Context, projectID int64, email string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&Invitation{}).
+		Where("project_id = ? AND LOWER(email) = LOWER(?) AND "+pendingCondition, projectID, email, time.Now()).
+		Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("HasPending: %v", err)
+	}
+	return count > 0, nil
+}
+
+func (r *Repository) ListPendingByProject(ctx context.Context, projectID int64) ([]*Invitation, error) {
+	var invitations []*Invitation
+	err := r.db.WithContext(ctx).Where("project_id = ? AND "+pendingCondition, projectID, time.Now()).
+		Order("created_at DESC").Find(&invitations).Error
+	if err != nil {
+		return nil, fmt.Errorf("ListPendingByProject: %v", err)
+	}
+	return invitations, nil
+}
+
+// ListPendingByEmail also returns the invitations sent before the address
+// had an account, which is how people pick up invites after signing up
+func (r *Repository) ListPendingByEmail(ctx context.Context, email string) ([]*InvitationResponse, error) {
+	var rows []*invitationWithProject
+	err := r.db.WithContext(ctx).Table("project_invitation").
+		Select("project_invitation.*, project.name AS project_name").
+		Joins("JOIN project ON project.id = project_invitation.project_id AND project.deleted_at IS NULL").
+		Where("LOWER(project_invitation.email) = LOWER(?)", email).
+		Where("project_invitation.accepted_at IS NULL AND project_invitation.declined_at IS NULL").
+		Where("project_invitation.revoked_at IS NULL AND project_invitation.expires_at > ?", time.Now()).
+		Order("project_invitation.created_at DESC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("ListPendingByEmail: %v", err)
+	}
+
+	responses := make([]*InvitationResponse, len(rows))
+	for i, row := range rows {
+		responses[i] = ToInvitationResponse(&row.Invitation)
+		responses[i].ProjectName = row.ProjectName
+	}
+	return responses, nil
+}
+
+// setIfPending stamps column on a pending invitation and reports whether it was still pending
+func (r *Repository) setIfPending(tx *gorm.DB, id int64, column string) (bool, error) {
+	now := time.Now()
+	res := tx.Model(&Invitation{}).Where("id = ? AND "+pendingCondition, id, now).Update(column, now)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+func (r *Repository) Revoke(ctx context.Context, id int64) (bool, error) {
+	ok, err := r.setIfPending(r.db.WithContext(ctx), id, "revoked_at")
+	if err != nil {
+		return false, fmt.Errorf("Revoke: %v", err)
+	}
+	return ok, nil
+}
+
+func (r *Repository) Decline(ctx context.Context, id int64) (bool, error) {
+	ok, err := r.setIfPending(r.db.WithContext(ctx), id, "declined_at")
+	if err != nil {
+		return false, fmt.Errorf("Decline: %v", err)
+	}
+	return ok, nil
+}
+
+// Accept marks the invitation accepted and adds the user to the project in
+// one transaction. Someone who is already a member keeps their current role:
+// the invitation is still used up, but Accept reports that nothing changed.
+func (r *Repository) Accept(ctx context.Context, invitation *Invitation, userID uuid.UUID) (bool, error) {
+	accepted, added := false, false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ok, err := r.setIfPending(tx, invitation.ID, "accepted_at")
+		if err != nil || !ok {
+			return err
+		}
+		accepted = true
+
+		member := &project.ProjectMember{ProjectID: invitation.ProjectID, UserID: userID, Role: invitation.Role}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(member)
+		added = result.RowsAffected > 0
+		return result.Error
+	})
+	if err != nil {
+		return false, fmt.Errorf("Accept: %v", err)
+	}
+	if accepted && !added {
+		return true, fmt.Errorf("member already added to project - Accept")
+	}
+	return accepted, nil
+}
@@ -0,0 +1,22 @@
+package invitation
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRoutes(group *gin.RouterGroup, controller *Controller,
+	authMw gin.HandlerFunc, scopeMw gin.HandlerFunc, rateLimitMw gin.HandlerFunc) {
+	invitations := group.Group("")
+	invitations.Use(authMw)
+	invitations.Use(scopeMw)
+	invitations.Use(rateLimitMw)
+	{
+		invitations.POST("/projects/:id/invitations", controller.Create)
+		invitations.GET("/projects/:id/invitations", controller.ListForProject)
+		invitations.DELETE("/projects/:id/invitations/:invitationId", controller.Revoke)
+		invitations.GET("/users/me/invitations", controller.ListMine)
+		invitations.POST("/users/me/invitations/:id/accept", controller.Accept)
+		invitations.POST("/users/me/invitations/:id/decline", controller.Decline)
+		invitations.POST("/invitations/accept", controller.AcceptByToken)
+	}
+}
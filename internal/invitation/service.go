@@ -0,0 +1,264 @@
+package invitation
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"task-management/internal/config"
+	"task-management/internal/mailer"
+	"task-management/internal/project"
+	"task-management/internal/user"
+	"task-management/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+type Service interface {
+	Create(ctx context.Context, projectID int64, userID uuid.UUID, dto *CreateInvitationRequest) (*InvitationResponse, error)
+	ListForProject(ctx context.Context, projectID int64, userID uuid.UUID) ([]*InvitationResponse, error)
+	Revoke(ctx context.Context, projectID int64, userID uuid.UUID, invitationID int64) error
+	// ListMine returns the pending invitations sent to the user's email address
+	ListMine(ctx context.Context, userID uuid.UUID) ([]*InvitationResponse, error)
+	Accept(ctx context.Context, userID uuid.UUID, invitationID int64) error
+	Decline(ctx context.Context, userID uuid.UUID, invitationID int64) error
+	// AcceptByToken accepts with the token from the invitation mail, which
+	// proves access to the invited mailbox whatever address the account uses
+	AcceptByToken(ctx context.Context, userID uuid.UUID, token string) (*InvitationResponse, error)
+}
+
+type service struct {
+	config      *config.Config
+	repo        *Repository
+	projectRepo *project.Repository
+	userRepo    *user.Repository
+	mailer      mailer.Mailer
+}
+
+func NewService(config *config.Config, repo *Repository, projectRepo *project.Repository, userRepo *user.Repository, mailer mailer.Mailer) Service {
+	return &service{
+		config:      config,
+		repo:        repo,
+		projectRepo: projectRepo,
+		userRepo:    userRepo,
+		mailer:      mailer,
+	}
+}
+
+func (s *service) Create(ctx context.Context, projectID int64, userID uuid.UUID, dto *CreateInvitationRequest) (*InvitationResponse, error) {
+	p, role, err := s.projectRepo.Authorize(ctx, projectID, userID, project.ActionManageMembers)
+	if err != nil {
+		return nil, fmt.Errorf("Create: %v", err)
+	}
+
+	newRole := dto.Role
+	if newRole == "" {
+		newRole = project.RoleEditor
+	}
+	if role != project.RoleOwner && !project.Outranks(role, newRole) {
+		return nil, fmt.Errorf("unauthorized: %s role cannot grant %s - Create", role, newRole)
+	}
+
+	email := strings.TrimSpace(dto.Email)
+	invitee, err := s.userRepo.FindByEmail(ctx, email)
+	if err != nil && err.Error() != "user not found" {
+		return nil, fmt.Errorf("Create: %v", err)
+	}
+	if invitee != nil {
+		inviteeRole, err := s.projectRepo.RoleOf(ctx, p, invitee.ID)
+		if err != nil {
+			return nil, fmt.Errorf("Create: %v", err)
+		}
+		if inviteeRole != "" {
+			return nil, fmt.Errorf("member already added to project - Create")
+		}
+	}
+
+	pending, err := s.repo.HasPending(ctx, projectID, email)
+	if err != nil {
+		return nil, fmt.Errorf("Create: %v", err)
+	}
+	if pending {
+		return nil, fmt.Errorf("invitation already pending for this email - Create")
+	}
+
+	token, tokenHash, err := utils.NewOpaqueToken()
+	if err != nil {
+		return nil, fmt.Errorf("Create: %v", err)
+	}
+
+	now := time.Now()
+	invitation := &Invitation{
+		ProjectID: projectID,
+		InviterID: userID,
+		Email:     email,
+		Role:      newRole,
+		TokenHash: tokenHash,
+		ExpiresAt: now.Add(time.Hour * time.Duration(s.config.Project.InvitationTTLHours)),
+		CreatedAt: now,
+	}
+	inviter, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("Create: %v", err)
+	}
+
+	if err := s.repo.Create(ctx, invitation); err != nil {
+		return nil, fmt.Errorf("Create: %v", err)
+	}
+
+	err = s.mailer.Send(ctx, &mailer.Message{
+		To:      email,
+		Subject: fmt.Sprintf("%s invited you to %s", inviter.Name, p.Name),
+		Body: fmt.Sprintf("Hi,\n\n%s invited you to join the project %q as %s.\n\n"+
+			"Sign in to %s with this address (or create an account with it) to find the invitation "+
+			"among your pending invitations, or accept it from any account with this code:\n\n%s\n\n"+
+			"The invitation expires in %d hours.\n",
+			inviter.Name, p.Name, newRole, s.config.Server.PublicURL, token, s.config.Project.InvitationTTLHours),
+	})
+	if err != nil {
+		// an unsent invitation must not block inviting the address again
+		if _, revokeErr := s.repo.Revoke(ctx, invitation.ID); revokeErr != nil {
+			log.Printf("failed to revoke unsent invitation %d - Create: %v", invitation.ID, revokeErr)
+		}
+		return nil, fmt.Errorf("failed to send invitation mail - Create: %v", err)
+	}
+
+	return ToInvitationResponse(invitation), nil
+}
+
+func (s *service) ListForProject(ctx context.Context, projectID int64, userID uuid.UUID) ([]*InvitationResponse, error) {
+	if _, _, err := s.projectRepo.Authorize(ctx, projectID, userID, project.ActionManageMembers); err != nil {
+		return nil, fmt.Errorf("ListForProject: %v", err)
+	}
+
+	invitations, err := s.repo.ListPendingByProject(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("ListForProject: %v", err)
+	}
+
+	return ToInvitationResponseList(invitations), nil
+}
+
+func (s *service) Revoke(ctx context.Context, projectID int64, userID uuid.UUID, invitationID int64) error {
+	if _, _, err := s.projectRepo.Authorize(ctx, projectID, userID, project.ActionManageMembers); err != nil {
+		return fmt.Errorf("Revoke: %v", err)
+	}
+
+	invitation, err := s.repo.FindByID(ctx, invitationID)
+	if err != nil {
+		return fmt.Errorf("Revoke: %v", err)
+	}
+	if invitation.ProjectID != projectID {
+		return fmt.Errorf("invitation not found - Revoke")
+	}
+
+	revoked, err := s.repo.Revoke(ctx, invitationID)
+	if err != nil {
+		return fmt.Errorf("Revoke: %v", err)
+	}
+	if !revoked {
+		return fmt.Errorf("invitation is no longer pending - Revoke")
+	}
+
+	return nil
+}
+
+func (s *service) ListMine(ctx context.Context, userID uuid.UUID) ([]*InvitationResponse, error) {
+	userInfo, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("ListMine: %v", err)
+	}
+
+	invitations, err := s.repo.ListPendingByEmail(ctx, userInfo.Email)
+	if err != nil {
+		return nil, fmt.Errorf("ListMine: %v", err)
+	}
+
+	return invitations, nil
+}
+
+// addressedTo loads an invitation and checks it was sent to the user's email
+func (s *service) addressedTo(ctx context.Context, userID uuid.UUID, invitationID int64) (*Invitation, *user.User, error) {
+	userInfo, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	invitation, err := s.repo.FindByID(ctx, invitationID)
+	if err != nil {
+		return nil, nil, err
+	}
+	// don't reveal invitations sent to other people
+	if !strings.EqualFold(invitation.Email, userInfo.Email) {
+		return nil, nil, fmt.Errorf("invitation not found")
+	}
+
+	return invitation, userInfo, nil
+}
+
+func (s *service) Accept(ctx context.Context, userID uuid.UUID, invitationID int64) error {
+	invitation, userInfo, err := s.addressedTo(ctx, userID, invitationID)
+	if err != nil {
+		return fmt.Errorf("Accept: %v", err)
+	}
+
+	// without a verified address the email match proves nothing
+	if s.config.Auth.RestrictUnverified && userInfo.EmailVerifiedAt == nil {
+		return fmt.Errorf("user email not verified - Accept")
+	}
+
+	return s.accept(ctx, invitation, userID)
+}
+
+func (s *service) AcceptByToken(ctx context.Context, userID uuid.UUID, token string) (*InvitationResponse, error) {
+	invitation, err := s.repo.FindPendingByToken(ctx, utils.HashToken(token))
+	if err != nil {
+		return nil, fmt.Errorf("AcceptByToken: %v", err)
+	}
+
+	if err := s.accept(ctx, invitation, userID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	invitation.AcceptedAt = &now
+	return ToInvitationResponse(invitation), nil
+}
+
+func (s *service) accept(ctx context.Context, invitation *Invitation, userID uuid.UUID) error {
+	p, err := s.projectRepo.FindByID(ctx, invitation.ProjectID)
+	if err != nil {
+		return fmt.Errorf("accept: %v", err)
+	}
+	if p.OwnerID == userID {
+		return fmt.Errorf("member already added to project - accept")
+	}
+
+	accepted, err := s.repo.Accept(ctx, invitation, userID)
+	if err != nil {
+		return fmt.Errorf("accept: %v", err)
+	}
+	if !accepted {
+		return fmt.Errorf("invitation is no longer pending - accept")
+	}
+
+	return nil
+}
+
+func (s *service) Decline(ctx context.Context, userID uuid.UUID, invitationID int64) error {
+	if _, _, err := s.addressedTo(ctx, userID, invitationID); err != nil {
+		return fmt.Errorf("Decline: %v", err)
+	}
+
+	declined, err := s.repo.Decline(ctx, invitationID)
+	if err != nil {
+		return fmt.Errorf("Decline: %v", err)
+	}
+	if !declined {
+		return fmt.Errorf("invitation is no longer pending - Decline")
+	}
+
+	return nil
+}
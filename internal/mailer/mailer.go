@@ -0,0 +1,31 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+
+	"task-management/internal/config"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// New picks the delivery backend from MAIL_DRIVER: "smtp" for real delivery,
+// "outbox" to write messages to a local file and the log (dev and tests)
+func New(config *config.Config) (Mailer, error) {
+	switch config.Mail.Driver {
+	case "smtp":
+		return NewSMTPMailer(config), nil
+	case "outbox":
+		return NewOutboxMailer(config), nil
+	default:
+		return nil, fmt.Errorf("unknown mail driver %q - New", config.Mail.Driver)
+	}
+}
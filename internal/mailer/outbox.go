@@ -0,0 +1,48 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"task-management/internal/config"
+)
+
+// outboxMailer never talks to a mail server. Every message is logged and,
+// when a path is configured, appended to an outbox file for inspection.
+type outboxMailer struct {
+	mu   sync.Mutex
+	path string
+	from string
+}
+
+func NewOutboxMailer(config *config.Config) Mailer {
+	return &outboxMailer{path: config.Mail.OutboxPath, from: config.Mail.From}
+}
+
+func (m *outboxMailer) Send(ctx context.Context, msg *Message) error {
+	log.Printf("outbox mail to=%s subject=%q", msg.To, msg.Subject)
+
+	if m.path == "" {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	f, err := os.OpenFile(m.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("outbox - Send: %v", err)
+	}
+	defer f.Close()
+
+	_, err = fmt.Fprintf(f, "Date: %s\nFrom: %s\nTo: %s\nSubject: %s\n\n%s\n\n----\n",
+		time.Now().Format(time.RFC1123Z), m.from, msg.To, msg.Subject, msg.Body)
+	if err != nil {
+		return fmt.Errorf("outbox - Send: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+
+	"task-management/internal/config"
+)
+
+type smtpMailer struct {
+	addr     string
+	host     string
+	username string
+	password string
+	from     string
+}
+
+func NewSMTPMailer(config *config.Config) Mailer {
+	return &smtpMailer{
+		addr:     net.JoinHostPort(config.Mail.Host, config.Mail.Port),
+		host:     config.Mail.Host,
+		username: config.Mail.Username,
+		password: config.Mail.Password,
+		from:     config.Mail.From,
+	}
+}
+
+func (m *smtpMailer) Send(ctx context.Context, msg *Message) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("smtp - Send: %v", err)
+	}
+
+	var auth smtp.Auth
+	if m.username != "" {
+		auth = smtp.PlainAuth("", m.username, m.password, m.host)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", m.from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(msg.Body)
+
+	err := smtp.SendMail(m.addr, auth, m.from, []string{msg.To}, []byte(b.String()))
+	if err != nil {
+		return fmt.Errorf("smtp - Send: %v", err)
+	}
+	return nil
+}
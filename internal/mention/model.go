@@ -0,0 +1,37 @@
+package mention
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Mention links a mentioned user to the text that mentions them: a task's
+// description, a subtask's description or a comment
+type Mention struct {
+	ID        int64 `gorm:"primaryKey;autoIncrement"`
+	TaskID    int64 `gorm:"not null"`
+	SubtaskID *int64
+	CommentID *int64
+	UserID    uuid.UUID `gorm:"type:uuid;not null"`
+	CreatedAt time.Time `gorm:"type:timestamp;not null"`
+}
+
+func (Mention) TableName() string {
+	return "mention"
+}
+
+// Source is the text mentions are taken from
+type Source struct {
+	ProjectID int64
+	TaskID    int64
+	SubtaskID *int64
+	CommentID *int64
+}
+
+// person is a project member as far as resolving mentions goes
+type person struct {
+	ID     uuid.UUID
+	Handle *string
+	Email  string
+}
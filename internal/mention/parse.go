@@ -0,0 +1,62 @@
+package mention
+
+import (
+	"regexp"
+	"strings"
+)
+
+// mentionPattern finds @name not preceded by a word character, so the
+// domain part of an email address is not taken for a mention
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@.])@([\w][\w.-]*)`)
+
+// Parse returns the lowercased names mentioned in text, each once, in order
+func Parse(text string) []string {
+	seen := map[string]bool{}
+	names := []string{}
+	for _, match := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		// "@alice." at the end of a sentence mentions alice
+		name := strings.ToLower(strings.TrimRight(match[1], ".-"))
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names
+}
+
+// resolve matches names to people by handle first and otherwise by the part
+// of their email before the @. A prefix shared by several people is ambiguous
+// and mentions nobody.
+func resolve(names []string, people []person) []person {
+	byHandle := map[string]person{}
+	byPrefix := map[string][]person{}
+	for _, p := range people {
+		if p.Handle != nil {
+			byHandle[strings.ToLower(*p.Handle)] = p
+		}
+		prefix := strings.ToLower(p.Email)
+		if at := strings.Index(prefix, "@"); at >= 0 {
+			prefix = prefix[:at]
+		}
+		byPrefix[prefix] = append(byPrefix[prefix], p)
+	}
+
+	seen := map[string]bool{}
+	var mentioned []person
+	for _, name := range names {
+		p, ok := byHandle[name]
+		if !ok {
+			if len(byPrefix[name]) != 1 {
+				continue
+			}
+			p = byPrefix[name][0]
+		}
+		if seen[p.ID.String()] {
+			continue
+		}
+		seen[p.ID.String()] = true
+		mentioned = append(mentioned, p)
+	}
+	return mentioned
+}
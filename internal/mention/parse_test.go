@@ -0,0 +1,77 @@
+package mention
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"no mentions", "nothing to see here", []string{}},
+		{"at the start", "@alice please review", []string{"alice"}},
+		{"inside a sentence", "thanks @bob for this", []string{"bob"}},
+		{"several", "@alice and @bob", []string{"alice", "bob"}},
+		{"lowercased", "ping @Alice", []string{"alice"}},
+		{"duplicates once, in order", "@bob @alice @BOB @bob", []string{"bob", "alice"}},
+		{"trailing full stop", "ask @alice.", []string{"alice"}},
+		{"trailing punctuation", "@alice, @bob! @carol? (@dave) @erin:", []string{"alice", "bob", "carol", "dave", "erin"}},
+		{"trailing dash", "@alice- see above", []string{"alice"}},
+		{"dots and dashes inside", "@jane.doe and @jean-luc", []string{"jane.doe", "jean-luc"}},
+		{"email address", "mail alice@example.com", []string{}},
+		{"email address next to a mention", "@bob mail alice@example.com", []string{"bob"}},
+		{"after a dot", "see.@alice", []string{}},
+		{"double at", "@@alice", []string{}},
+		{"bare at", "meet @ noon", []string{}},
+		{"on a new line", "first line\n@alice", []string{"alice"}},
+		{"underscore and digits", "@dev_42", []string{"dev_42"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse(tt.text); !slices.Equal(got, tt.want) {
+				t.Errorf("Parse(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	handle := func(h string) *string { return &h }
+	alice := person{ID: uuid.New(), Handle: handle("alice"), Email: "alice.smith@example.com"}
+	bob := person{ID: uuid.New(), Handle: handle("Bob"), Email: "robert@example.com"}
+	carol := person{ID: uuid.New(), Email: "carol@example.com"}
+	sam1 := person{ID: uuid.New(), Email: "sam@example.com"}
+	sam2 := person{ID: uuid.New(), Email: "Sam@example.org"}
+	// dave's email prefix is someone else's handle
+	dave := person{ID: uuid.New(), Email: "alice@example.net"}
+	people := []person{alice, bob, carol, sam1, sam2, dave}
+
+	tests := []struct {
+		name  string
+		names []string
+		want  []person
+	}{
+		{"by handle", []string{"alice"}, []person{alice}},
+		{"handle is case-insensitive", []string{"bob"}, []person{bob}},
+		{"by email prefix without a handle", []string{"carol"}, []person{carol}},
+		{"email prefix of someone with a handle", []string{"robert"}, []person{bob}},
+		{"handle wins over an email prefix", []string{"alice"}, []person{alice}},
+		{"ambiguous email prefix", []string{"sam"}, nil},
+		{"unknown", []string{"zoe"}, nil},
+		{"same person twice", []string{"bob", "robert"}, []person{bob}},
+		{"order kept, unknown skipped", []string{"carol", "zoe", "alice"}, []person{carol, alice}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := resolve(tt.names, people)
+			if !slices.EqualFunc(got, tt.want, func(a, b person) bool { return a.ID == b.ID }) {
+				t.Errorf("resolve(%q) = %v, want %v", tt.names, got, tt.want)
+			}
+		})
+	}
+}
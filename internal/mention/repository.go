@@ -0,0 +1,103 @@
+package mention
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{db: db}
+}
+
+// People lists the owner and members of a project, who are the only ones
+// that can be mentioned in it
+func (r *Repository) People(ctx context.Context, projectID int64) ([]person, error) {
+	var people []person
+	err := r.db.WithContext(ctx).Table("app_user").Select("id, handle, email").
+		Where(`id IN (SELECT owner_id FROM project WHERE id = ?)
+			OR id IN (SELECT user_id FROM project_member WHERE project_id = ?)`, projectID, projectID).
+		Scan(&people).Error
+	if err != nil {
+		return nil, fmt.Errorf("People: %v", err)
+	}
+	return people, nil
+}
+
+// bySource narrows a query down to the mentions of one source
+func bySource(query *gorm.DB, source Source) *gorm.DB {
+	query = query.Where("task_id = ?", source.TaskID)
+	if source.SubtaskID != nil {
+		query = query.Where("subtask_id = ?", *source.SubtaskID)
+	} else {
+		query = query.Where("subtask_id IS NULL")
+	}
+	if source.CommentID != nil {
+		query = query.Where("comment_id = ?", *source.CommentID)
+	} else {
+		query = query.Where("comment_id IS NULL")
+	}
+	return query
+}
+
+// Replace makes userIDs the mentions of source and returns the ones that
+// were not mentioned there before
+func (r *Repository) Replace(ctx context.Context, source Source, userIDs []uuid.UUID) ([]uuid.UUID, error) {
+	var added []uuid.UUID
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing []uuid.UUID
+		if err := bySource(tx.Model(&Mention{}), source).Pluck("user_id", &existing).Error; err != nil {
+			return err
+		}
+
+		keep := map[uuid.UUID]bool{}
+		for _, id := range userIDs {
+			keep[id] = true
+		}
+		had := map[uuid.UUID]bool{}
+		var removed []uuid.UUID
+		for _, id := range existing {
+			had[id] = true
+			if !keep[id] {
+				removed = append(removed, id)
+			}
+		}
+
+		if len(removed) > 0 {
+			if err := bySource(tx, source).Where("user_id IN ?", removed).Delete(&Mention{}).Error; err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		var rows []*Mention
+		for _, id := range userIDs {
+			if had[id] {
+				continue
+			}
+			added = append(added, id)
+			rows = append(rows, &Mention{
+				TaskID:    source.TaskID,
+				SubtaskID: source.SubtaskID,
+				CommentID: source.CommentID,
+				UserID:    id,
+				CreatedAt: now,
+			})
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		return tx.Create(&rows).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("mention - Replace: %v", err)
+	}
+	return added, nil
+}
@@ -0,0 +1,59 @@
+package mention
+
+import (
+	"context"
+	"fmt"
+
+	"task-management/internal/notification"
+
+	"github.com/google/uuid"
+)
+
+type Service interface {
+	// Sync records who text mentions and notifies the people it newly
+	// mentions, returning them so the caller can have them watch the task.
+	// A nil text leaves the mentions of source as they are.
+	Sync(ctx context.Context, actorID uuid.UUID, source Source, text *string) ([]uuid.UUID, error)
+}
+
+type service struct {
+	repo          *Repository
+	notifications notification.Service
+}
+
+func NewService(repo *Repository, notifications notification.Service) Service {
+	return &service{repo: repo, notifications: notifications}
+}
+
+func (s *service) Sync(ctx context.Context, actorID uuid.UUID, source Source, text *string) ([]uuid.UUID, error) {
+	if text == nil {
+		return nil, nil
+	}
+
+	var mentioned []person
+	if names := Parse(*text); len(names) > 0 {
+		people, err := s.repo.People(ctx, source.ProjectID)
+		if err != nil {
+			return nil, fmt.Errorf("Sync: %v", err)
+		}
+		mentioned = resolve(names, people)
+	}
+
+	userIDs := make([]uuid.UUID, len(mentioned))
+	for i, p := range mentioned {
+		userIDs[i] = p.ID
+	}
+
+	added, err := s.repo.Replace(ctx, source, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("Sync: %v", err)
+	}
+
+	target := notification.Target{ProjectID: source.ProjectID, TaskID: &source.TaskID, SubtaskID: source.SubtaskID,
+		CommentID: source.CommentID}
+	if err := s.notifications.NotifyMany(ctx, actorID, added, notification.TypeMention, target, nil); err != nil {
+		// the mentions are recorded, so the caller still gets them
+		return added, fmt.Errorf("Sync: %v", err)
+	}
+	return added, nil
+}
@@ -1,15 +1,19 @@
 package middleware
 
 import (
+	"log"
 	"strings"
 
-	"task-management/internal/config"
+	"task-management/internal/accesstoken"
+	"task-management/internal/session"
 	"task-management/internal/utils"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
 )
 
-func AuthMiddleware(config *config.Config) gin.HandlerFunc {
+func AuthMiddleware(keys *utils.KeySet, rdb *redis.Client, tokens accesstoken.Service, sessions session.Service) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -27,15 +31,80 @@ func AuthMiddleware(config *config.Config) gin.HandlerFunc {
 			return
 		}
 
-		token, err := utils.ParseToken(parts[1], *config)
+		if strings.HasPrefix(parts[1], accesstoken.TokenPrefix) {
+			pat, err := tokens.Authenticate(c.Request.Context(), parts[1])
+			if err != nil {
+				c.AbortWithStatusJSON(401, gin.H{
+					"error": "token invalid or expired",
+				})
+				return
+			}
+
+			c.Set("userID", pat.UserID)
+			c.Set("tokenScopes", pat.ScopeList())
+			c.Next()
+			return
+		}
+
+		token, err := keys.ParseToken(parts[1])
+		if err != nil {
+			log.Printf("AuthMiddleware: %v", err)
+			c.AbortWithStatusJSON(401, gin.H{
+				"error": "token invalid or expired",
+			})
+			return
+		}
+
+		if token.Purpose != utils.PurposeAccess {
+			c.AbortWithStatusJSON(401, gin.H{
+				"error": "token is not an access token",
+			})
+			return
+		}
+
+		revoked, err := utils.IsTokenRevoked(c.Request.Context(), rdb, token.ID)
+		if err != nil {
+			c.AbortWithStatusJSON(500, gin.H{
+				"error": "failed to check token status",
+			})
+			return
+		}
+		if revoked {
+			c.AbortWithStatusJSON(401, gin.H{
+				"error": "token has been revoked",
+			})
+			return
+		}
+
+		if token.SessionID == uuid.Nil {
+			c.AbortWithStatusJSON(401, gin.H{
+				"error": "token has no session",
+			})
+			return
+		}
+
+		revoked, err = sessions.IsRevoked(c.Request.Context(), token.SessionID)
 		if err != nil {
+			c.AbortWithStatusJSON(500, gin.H{
+				"error": "failed to check session status",
+			})
+			return
+		}
+		if revoked {
 			c.AbortWithStatusJSON(401, gin.H{
-				"error": "token invalid or expired" + err.Error(),
+				"error": "session has been revoked",
 			})
 			return
 		}
 
+		if err := sessions.Touch(c.Request.Context(), token.SessionID, c.ClientIP()); err != nil {
+			log.Printf("AuthMiddleware: %v", err)
+		}
+
 		c.Set("userID", token.UserID)
+		c.Set("sessionID", token.SessionID)
+		c.Set("tokenID", token.ID)
+		c.Set("tokenExpiresAt", token.ExpiresAt.Time)
 		c.Next()
 
 	}
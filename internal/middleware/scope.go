@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"slices"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireScope limits what personal access tokens can do on a route group.
+// JWT sessions are never restricted. Any token may read; writes need the
+// given scope, and an empty scope means no token may write at all.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, ok := c.Get("tokenScopes")
+		if !ok {
+			c.Next()
+			return
+		}
+		scopes := value.([]string)
+
+		switch c.Request.Method {
+		case "GET", "HEAD", "OPTIONS":
+			c.Next()
+			return
+		}
+
+		if scope == "" || !slices.Contains(scopes, scope) {
+			c.AbortWithStatusJSON(403, gin.H{
+				"error": "insufficient token scope",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireSession keeps personal access tokens away from a route group
+// altogether, reads included. It is meant for the routes that manage the
+// account: its tokens, sessions, two-factor settings and login history.
+func RequireSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := c.Get("tokenScopes"); ok {
+			c.AbortWithStatusJSON(403, gin.H{
+				"error": "not available to access tokens",
+			})
+			return
+		}
+		c.Next()
+	}
+}
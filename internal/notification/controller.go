@@ -0,0 +1,162 @@
+package notification
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type Controller struct {
+	service Service
+}
+
+func NewController(service Service) *Controller {
+	return &Controller{service: service}
+}
+
+// List returns the inbox with the unread count
+// GET /users/me/notifications?unread=true&limit=50
+func (controller *Controller) List(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.IndentedJSON(401, gin.H{"error": "unauthorized"})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+
+	unreadOnly := false
+	if raw := c.Query("unread"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.IndentedJSON(400, gin.H{"error": "unread must be true or false"})
+			return
+		}
+		unreadOnly = parsed
+	}
+
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 {
+			c.IndentedJSON(400, gin.H{"error": "limit must be a positive number"})
+			return
+		}
+		limit = parsed
+	}
+
+	inbox, err := controller.service.List(c.Request.Context(), userUUID, unreadOnly, limit)
+	if err != nil {
+		c.IndentedJSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.IndentedJSON(200, inbox)
+}
+
+func (controller *Controller) UnreadCount(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.IndentedJSON(401, gin.H{"error": "unauthorized"})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+
+	count, err := controller.service.UnreadCount(c.Request.Context(), userUUID)
+	if err != nil {
+		c.IndentedJSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.IndentedJSON(200, gin.H{"unread_count": count})
+}
+
+func (controller *Controller) MarkRead(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.IndentedJSON(401, gin.H{"error": "unauthorized"})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.IndentedJSON(400, gin.H{"error": "invalid notification id"})
+		return
+	}
+
+	if err := controller.service.MarkRead(c.Request.Context(), userUUID, id); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.IndentedJSON(404, gin.H{"error": err.Error()})
+			return
+		}
+		c.IndentedJSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.IndentedJSON(200, gin.H{"message": "notification marked as read"})
+}
+
+func (controller *Controller) MarkAllRead(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.IndentedJSON(401, gin.H{"error": "unauthorized"})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+
+	count, err := controller.service.MarkAllRead(c.Request.Context(), userUUID)
+	if err != nil {
+		c.IndentedJSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.IndentedJSON(200, gin.H{"marked_read": count})
+}
+
+func (controller *Controller) Preferences(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.IndentedJSON(401, gin.H{"error": "unauthorized"})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+
+	preferences, err := controller.service.Preferences(c.Request.Context(), userUUID)
+	if err != nil {
+		c.IndentedJSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.IndentedJSON(200, preferences)
+}
+
+// UpdatePreferences takes an object of notification type to true or false
+// PUT /users/me/notification-preferences
+func (controller *Controller) UpdatePreferences(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.IndentedJSON(401, gin.H{"error": "unauthorized"})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+
+	var req map[string]bool
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.IndentedJSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	preferences, err := controller.service.UpdatePreferences(c.Request.Context(), userUUID, req)
+	if err != nil {
+		if strings.Contains(err.Error(), "unknown notification type") {
+			c.IndentedJSON(400, gin.H{"error": err.Error()})
+			return
+		}
+		c.IndentedJSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.IndentedJSON(200, preferences)
+}
@@ -0,0 +1,52 @@
+package notification
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NotificationResponse struct {
+	ID        int64                  `json:"id"`
+	Type      string                 `json:"type"`
+	ActorID   *uuid.UUID             `json:"actor_id"`
+	ProjectID int64                  `json:"project_id"`
+	TaskID    *int64                 `json:"task_id"`
+	SubtaskID *int64                 `json:"subtask_id"`
+	CommentID *int64                 `json:"comment_id"`
+	Details   map[string]interface{} `json:"details"`
+	Read      bool                   `json:"read"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+type InboxResponse struct {
+	UnreadCount   int64                   `json:"unread_count"`
+	Notifications []*NotificationResponse `json:"notifications"`
+}
+
+func ToNotificationResponse(notification *Notification) *NotificationResponse {
+	details := map[string]interface{}{}
+	_ = json.Unmarshal([]byte(notification.Details), &details)
+
+	return &NotificationResponse{
+		ID:        notification.ID,
+		Type:      notification.Type,
+		ActorID:   notification.ActorID,
+		ProjectID: notification.ProjectID,
+		TaskID:    notification.TaskID,
+		SubtaskID: notification.SubtaskID,
+		CommentID: notification.CommentID,
+		Details:   details,
+		Read:      notification.ReadAt != nil,
+		CreatedAt: notification.CreatedAt,
+	}
+}
+
+func ToNotificationResponseList(notifications []*Notification) []*NotificationResponse {
+	responses := make([]*NotificationResponse, len(notifications))
+	for i, notification := range notifications {
+		responses[i] = ToNotificationResponse(notification)
+	}
+	return responses
+}
@@ -0,0 +1,26 @@
+package notification
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// RunDueSoon checks for work that is due soon right away and then every
+// interval until ctx is cancelled. Run it in its own goroutine.
+func RunDueSoon(ctx context.Context, service Service, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := service.NotifyDueSoon(ctx); err != nil {
+			log.Printf("failed to send due soon notifications - RunDueSoon: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
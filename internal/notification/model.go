@@ -0,0 +1,53 @@
+package notification
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Kinds of notifications
+const (
+	TypeMention = "mention"
+	// TypeAssigned is sent to people a task or subtask is assigned to
+	TypeAssigned = "assigned"
+	// TypeStatusChanged and TypeUpdated are sent to a task's watchers when
+	// the task or one of its subtasks changes
+	TypeStatusChanged = "status_changed"
+	TypeUpdated       = "updated"
+	TypeDueSoon       = "due_soon"
+)
+
+// Types lists every notification type, in the order preferences are shown
+var Types = []string{TypeAssigned, TypeMention, TypeStatusChanged, TypeUpdated, TypeDueSoon}
+
+// Notification tells UserID about something ActorID did, or about something
+// due when ActorID is empty. The ids point at where it happened; only the
+// ones that apply are set.
+type Notification struct {
+	ID        int64      `gorm:"primaryKey;autoIncrement"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null"`
+	ActorID   *uuid.UUID `gorm:"type:uuid"`
+	Type      string     `gorm:"type:varchar(30);not null"`
+	ProjectID int64      `gorm:"not null"`
+	TaskID    *int64
+	SubtaskID *int64
+	CommentID *int64
+	// Details is a JSON object with what the type needs, such as the old and
+	// new status of a status change
+	Details   string     `gorm:"type:jsonb;not null;default:'{}'"`
+	ReadAt    *time.Time `gorm:"type:timestamp"`
+	CreatedAt time.Time  `gorm:"type:timestamp;not null"`
+}
+
+func (Notification) TableName() string {
+	return "notification"
+}
+
+// Target is where a change happened; only the ids that apply are set
+type Target struct {
+	ProjectID int64
+	TaskID    *int64
+	SubtaskID *int64
+	CommentID *int64
+}
@@ -0,0 +1,176 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"task-management/internal/user"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) Create(ctx context.Context, notifications []*Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	err := r.db.WithContext(ctx).Create(&notifications).Error
+	if err != nil {
+		return fmt.Errorf("notification - Create: %v", err)
+	}
+	return nil
+}
+
+// Muted returns the users among userIDs who turned notificationType off
+func (r *Repository) Muted(ctx context.Context, userIDs []uuid.UUID, notificationType string) ([]uuid.UUID, error) {
+	var muted []uuid.UUID
+	if len(userIDs) == 0 {
+		return muted, nil
+	}
+	err := r.db.WithContext(ctx).Model(&user.User{}).
+		Where("id IN ? AND notification_preferences ->> ? = 'false'", userIDs, notificationType).
+		Pluck("id", &muted).Error
+	if err != nil {
+		return nil, fmt.Errorf("Muted: %v", err)
+	}
+	return muted, nil
+}
+
+// FindByUserID lists the user's notifications, newest first
+func (r *Repository) FindByUserID(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit int) ([]*Notification, error) {
+	var notifications []*Notification
+	query := r.db.WithContext(ctx).Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+
+	err := query.Order("created_at DESC").Order("id DESC").Limit(limit).Find(&notifications).Error
+	if err != nil {
+		return nil, fmt.Errorf("FindByUserID: %v", err)
+	}
+	return notifications, nil
+}
+
+func (r *Repository) UnreadCount(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("UnreadCount: %v", err)
+	}
+	return count, nil
+}
+
+// MarkRead reports false when the user has no such notification
+func (r *Repository) MarkRead(ctx context.Context, userID uuid.UUID, id int64) (bool, error) {
+	var notification Notification
+	err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&notification).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("MarkRead: %v", err)
+	}
+	if notification.ReadAt != nil {
+		return true, nil
+	}
+
+	err = r.db.WithContext(ctx).Model(&notification).Update("read_at", time.Now()).Error
+	if err != nil {
+		return false, fmt.Errorf("MarkRead: %v", err)
+	}
+	return true, nil
+}
+
+// MarkAllRead returns how many notifications were unread
+func (r *Repository) MarkAllRead(ctx context.Context, userID uuid.UUID) (int64, error) {
+	res := r.db.WithContext(ctx).Model(&Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).Update("read_at", time.Now())
+	if res.Error != nil {
+		return 0, fmt.Errorf("MarkAllRead: %v", res.Error)
+	}
+	return res.RowsAffected, nil
+}
+
+func (r *Repository) Preferences(ctx context.Context, userID uuid.UUID) (string, error) {
+	var preferences []string
+	err := r.db.WithContext(ctx).Model(&user.User{}).Where("id = ?", userID).
+		Pluck("notification_preferences", &preferences).Error
+	if err != nil {
+		return "", fmt.Errorf("Preferences: %v", err)
+	}
+	if len(preferences) == 0 {
+		return "", fmt.Errorf("user not found")
+	}
+	return preferences[0], nil
+}
+
+func (r *Repository) SetPreferences(ctx context.Context, userID uuid.UUID, preferences string) error {
+	err := r.db.WithContext(ctx).Model(&user.User{}).Where("id = ?", userID).
+		Update("notification_preferences", preferences).Error
+	if err != nil {
+		return fmt.Errorf("SetPreferences: %v", err)
+	}
+	return nil
+}
+
+// DueSoon builds due_soon notifications for the unfinished tasks and
+// subtasks due before until, one per assignee, item and due date. The due
+// date goes into the details, so moving it brings a new reminder. Items in
+// archived or trashed projects are left out.
+func (r *Repository) DueSoon(ctx context.Context, until time.Time) ([]*Notification, error) {
+	now := time.Now()
+	var notifications []*Notification
+
+	var tasks []*Notification
+	err := r.db.WithContext(ctx).Table("task").
+		Select(`task_assignee.user_id, task.project_id, task.id AS task_id,
+			json_build_object('due_date', task.due_date)::text AS details`).
+		Joins("JOIN task_assignee ON task_assignee.task_id = task.id").
+		Joins("JOIN project ON project.id = task.project_id").
+		Where("task.deleted_at IS NULL AND task.completed = false").
+		Where("task.due_date > ? AND task.due_date <= ?", now, until).
+		Where("project.deleted_at IS NULL AND project.archived_at IS NULL").
+		Where(`NOT EXISTS (SELECT 1 FROM notification n WHERE n.type = ? AND n.user_id = task_assignee.user_id
+			AND n.task_id = task.id AND n.subtask_id IS NULL
+			AND (n.details->>'due_date')::timestamp = task.due_date)`, TypeDueSoon).
+		Scan(&tasks).Error
+	if err != nil {
+		return nil, fmt.Errorf("DueSoon: %v", err)
+	}
+	notifications = append(notifications, tasks...)
+
+	var subtasks []*Notification
+	err = r.db.WithContext(ctx).Table("subtask").
+		Select(`subtask.assigned_to AS user_id, task.project_id, task.id AS task_id, subtask.id AS subtask_id,
+			json_build_object('due_date', subtask.due_date)::text AS details`).
+		Joins("JOIN task ON task.id = subtask.task_id").
+		Joins("JOIN project ON project.id = task.project_id").
+		Where("subtask.assigned_to IS NOT NULL").
+		Where("subtask.deleted_at IS NULL AND task.deleted_at IS NULL AND subtask.completed = false").
+		Where("subtask.due_date > ? AND subtask.due_date <= ?", now, until).
+		Where("project.deleted_at IS NULL AND project.archived_at IS NULL").
+		Where(`NOT EXISTS (SELECT 1 FROM notification n WHERE n.type = ? AND n.user_id = subtask.assigned_to
+			AND n.subtask_id = subtask.id
+			AND (n.details->>'due_date')::timestamp = subtask.due_date)`, TypeDueSoon).
+		Scan(&subtasks).Error
+	if err != nil {
+		return nil, fmt.Errorf("DueSoon: %v", err)
+	}
+	notifications = append(notifications, subtasks...)
+
+	for _, n := range notifications {
+		n.Type = TypeDueSoon
+	}
+	return notifications, nil
+}
@@ -0,0 +1,21 @@
+package notification
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRoutes(group *gin.RouterGroup, controller *Controller,
+	authMw gin.HandlerFunc, scopeMw gin.HandlerFunc, rateLimitMw gin.HandlerFunc) {
+	users := group.Group("/users/me")
+	users.Use(authMw)
+	users.Use(scopeMw)
+	users.Use(rateLimitMw)
+	{
+		users.GET("/notifications", controller.List)
+		users.GET("/notifications/unread-count", controller.UnreadCount)
+		users.POST("/notifications/:id/read", controller.MarkRead)
+		users.POST("/notifications/read-all", controller.MarkAllRead)
+		users.GET("/notification-preferences", controller.Preferences)
+		users.PUT("/notification-preferences", controller.UpdatePreferences)
+	}
+}
@@ -0,0 +1,229 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"task-management/internal/config"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultInboxLimit = 50
+	maxInboxLimit     = 200
+)
+
+type Service interface {
+	// Notify stores notifications, leaving out the ones about the
+	// recipient's own actions and the types the recipient turned off
+	Notify(ctx context.Context, notifications ...*Notification) error
+	// NotifyMany tells each recipient about the same change by actorID to
+	// target; payload becomes the notifications' JSON details
+	NotifyMany(ctx context.Context, actorID uuid.UUID, recipients []uuid.UUID, notificationType string,
+		target Target, payload map[string]interface{}) error
+	List(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit int) (*InboxResponse, error)
+	UnreadCount(ctx context.Context, userID uuid.UUID) (int64, error)
+	MarkRead(ctx context.Context, userID uuid.UUID, id int64) error
+	MarkAllRead(ctx context.Context, userID uuid.UUID) (int64, error)
+	// Preferences has an entry for every type, true unless turned off
+	Preferences(ctx context.Context, userID uuid.UUID) (map[string]bool, error)
+	// UpdatePreferences changes the types given and keeps the others
+	UpdatePreferences(ctx context.Context, userID uuid.UUID, changes map[string]bool) (map[string]bool, error)
+	// NotifyDueSoon tells assignees about work due within the configured window
+	NotifyDueSoon(ctx context.Context) (int, error)
+}
+
+type service struct {
+	repo    *Repository
+	dueSoon time.Duration
+}
+
+func NewService(config *config.Config, repo *Repository) Service {
+	return &service{
+		repo:    repo,
+		dueSoon: time.Duration(config.Notification.DueSoonHours) * time.Hour,
+	}
+}
+
+func (s *service) Notify(ctx context.Context, notifications ...*Notification) error {
+	recipients := map[string][]uuid.UUID{}
+	for _, n := range notifications {
+		recipients[n.Type] = append(recipients[n.Type], n.UserID)
+	}
+	muted := map[string]map[uuid.UUID]bool{}
+	for notificationType, userIDs := range recipients {
+		ids, err := s.repo.Muted(ctx, userIDs, notificationType)
+		if err != nil {
+			return fmt.Errorf("Notify: %v", err)
+		}
+		muted[notificationType] = map[uuid.UUID]bool{}
+		for _, id := range ids {
+			muted[notificationType][id] = true
+		}
+	}
+
+	now := time.Now()
+	keep := make([]*Notification, 0, len(notifications))
+	for _, n := range notifications {
+		if n.ActorID != nil && *n.ActorID == n.UserID {
+			continue
+		}
+		if muted[n.Type][n.UserID] {
+			continue
+		}
+		if n.Details == "" {
+			n.Details = "{}"
+		}
+		n.CreatedAt = now
+		keep = append(keep, n)
+	}
+
+	if err := s.repo.Create(ctx, keep); err != nil {
+		return fmt.Errorf("Notify: %v", err)
+	}
+	return nil
+}
+
+func (s *service) NotifyMany(ctx context.Context, actorID uuid.UUID, recipients []uuid.UUID, notificationType string,
+	target Target, payload map[string]interface{}) error {
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	details := "{}"
+	if payload != nil {
+		raw, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("NotifyMany: %v", err)
+		}
+		details = string(raw)
+	}
+
+	notifications := make([]*Notification, len(recipients))
+	for i, userID := range recipients {
+		notifications[i] = &Notification{
+			UserID:    userID,
+			ActorID:   &actorID,
+			Type:      notificationType,
+			ProjectID: target.ProjectID,
+			TaskID:    target.TaskID,
+			SubtaskID: target.SubtaskID,
+			CommentID: target.CommentID,
+			Details:   details,
+		}
+	}
+	return s.Notify(ctx, notifications...)
+}
+
+func (s *service) List(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit int) (*InboxResponse, error) {
+	if limit <= 0 {
+		limit = defaultInboxLimit
+	}
+	if limit > maxInboxLimit {
+		limit = maxInboxLimit
+	}
+
+	notifications, err := s.repo.FindByUserID(ctx, userID, unreadOnly, limit)
+	if err != nil {
+		return nil, fmt.Errorf("List: %v", err)
+	}
+
+	unread, err := s.repo.UnreadCount(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("List: %v", err)
+	}
+
+	return &InboxResponse{
+		UnreadCount:   unread,
+		Notifications: ToNotificationResponseList(notifications),
+	}, nil
+}
+
+func (s *service) UnreadCount(ctx context.Context, userID uuid.UUID) (int64, error) {
+	count, err := s.repo.UnreadCount(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("UnreadCount: %v", err)
+	}
+	return count, nil
+}
+
+func (s *service) MarkRead(ctx context.Context, userID uuid.UUID, id int64) error {
+	found, err := s.repo.MarkRead(ctx, userID, id)
+	if err != nil {
+		return fmt.Errorf("MarkRead: %v", err)
+	}
+	if !found {
+		return fmt.Errorf("notification not found")
+	}
+	return nil
+}
+
+func (s *service) MarkAllRead(ctx context.Context, userID uuid.UUID) (int64, error) {
+	count, err := s.repo.MarkAllRead(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("MarkAllRead: %v", err)
+	}
+	return count, nil
+}
+
+func (s *service) Preferences(ctx context.Context, userID uuid.UUID) (map[string]bool, error) {
+	raw, err := s.repo.Preferences(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("Preferences: %v", err)
+	}
+
+	stored := map[string]bool{}
+	if err := json.Unmarshal([]byte(raw), &stored); err != nil {
+		return nil, fmt.Errorf("Preferences: %v", err)
+	}
+
+	preferences := make(map[string]bool, len(Types))
+	for _, notificationType := range Types {
+		enabled, ok := stored[notificationType]
+		preferences[notificationType] = !ok || enabled
+	}
+	return preferences, nil
+}
+
+func (s *service) UpdatePreferences(ctx context.Context, userID uuid.UUID, changes map[string]bool) (map[string]bool, error) {
+	known := map[string]bool{}
+	for _, notificationType := range Types {
+		known[notificationType] = true
+	}
+	for notificationType := range changes {
+		if !known[notificationType] {
+			return nil, fmt.Errorf("unknown notification type %q", notificationType)
+		}
+	}
+
+	preferences, err := s.Preferences(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("UpdatePreferences: %v", err)
+	}
+	for notificationType, enabled := range changes {
+		preferences[notificationType] = enabled
+	}
+
+	encoded, err := json.Marshal(preferences)
+	if err != nil {
+		return nil, fmt.Errorf("UpdatePreferences: %v", err)
+	}
+	if err := s.repo.SetPreferences(ctx, userID, string(encoded)); err != nil {
+		return nil, fmt.Errorf("UpdatePreferences: %v", err)
+	}
+	return preferences, nil
+}
+
+func (s *service) NotifyDueSoon(ctx context.Context) (int, error) {
+	notifications, err := s.repo.DueSoon(ctx, time.Now().Add(s.dueSoon))
+	if err != nil {
+		return 0, fmt.Errorf("NotifyDueSoon: %v", err)
+	}
+	if err := s.Notify(ctx, notifications...); err != nil {
+		return 0, fmt.Errorf("NotifyDueSoon: %v", err)
+	}
+	return len(notifications), nil
+}
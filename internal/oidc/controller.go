@@ -0,0 +1,86 @@
+package oidc
+
+import (
+	"strings"
+
+	"task-management/internal/session"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OIDCController struct {
+	service Service
+}
+
+func NewOIDCController(service Service) *OIDCController {
+	return &OIDCController{service: service}
+}
+
+// Start redirects the browser to the provider. API clients that want to
+// drive the redirect themselves can ask for JSON instead.
+func (controller *OIDCController) Start(c *gin.Context) {
+	authURL, err := controller.service.Start(c.Request.Context(), c.Param("provider"))
+	if err != nil {
+		if err.Error() == "unknown provider" {
+			c.IndentedJSON(404, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		c.IndentedJSON(502, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if strings.Contains(c.GetHeader("Accept"), "application/json") {
+		c.IndentedJSON(200, gin.H{"authorization_url": authURL})
+		return
+	}
+	c.Redirect(302, authURL)
+}
+
+func (controller *OIDCController) Callback(c *gin.Context) {
+	if providerErr := c.Query("error"); providerErr != "" {
+		c.IndentedJSON(400, gin.H{
+			"error":             "provider returned an error",
+			"provider_error":    providerErr,
+			"error_description": c.Query("error_description"),
+		})
+		return
+	}
+
+	client := &session.ClientInfo{IP: c.ClientIP(), UserAgent: c.Request.UserAgent()}
+	res, err := controller.service.Callback(c.Request.Context(), c.Param("provider"), c.Query("state"), c.Query("code"), client)
+	if err != nil {
+		switch {
+		case err.Error() == "unknown provider":
+			c.IndentedJSON(404, gin.H{
+				"error": err.Error(),
+			})
+		case err.Error() == "invalid or expired state":
+			c.IndentedJSON(400, gin.H{
+				"error": err.Error(),
+			})
+		case strings.Contains(err.Error(), "provider login failed"):
+			c.IndentedJSON(401, gin.H{
+				"error": err.Error(),
+			})
+		case err.Error() == "email not verified by provider":
+			c.IndentedJSON(403, gin.H{
+				"error": err.Error(),
+			})
+		case err.Error() == "local account email not verified":
+			c.IndentedJSON(409, gin.H{
+				"error": "an account with this email exists but has not verified it; log in to it (or reset its password) and verify the email first",
+			})
+		default:
+			c.IndentedJSON(500, gin.H{
+				"error": err.Error(),
+			})
+		}
+		return
+	}
+
+	c.IndentedJSON(200, res)
+}
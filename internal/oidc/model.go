@@ -0,0 +1,21 @@
+package oidc
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ExternalIdentity links an account at an identity provider to a local user
+type ExternalIdentity struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index"`
+	Provider  string    `gorm:"type:varchar(50);not null"`
+	Subject   string    `gorm:"type:varchar(255);not null"`
+	Email     string    `gorm:"type:varchar(255);not null"`
+	CreatedAt time.Time `gorm:"type:timestamp;not null"`
+}
+
+func (ExternalIdentity) TableName() string {
+	return "external_identity"
+}
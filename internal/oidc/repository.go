@@ -0,0 +1,37 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) FindIdentity(ctx context.Context, provider string, subject string) (*ExternalIdentity, error) {
+	var identity ExternalIdentity
+	err := r.db.WithContext(ctx).Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("FindIdentity: %v", err)
+	}
+	return &identity, nil
+}
+
+func (r *Repository) CreateIdentity(ctx context.Context, identity *ExternalIdentity) error {
+	err := r.db.WithContext(ctx).Create(identity).Error
+	if err != nil {
+		return fmt.Errorf("CreateIdentity: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+package oidc
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRoutes(group *gin.RouterGroup, controller *OIDCController, rateLimit gin.HandlerFunc) {
+	oidc := group.Group("/auth/oidc/:provider")
+	oidc.Use(rateLimit)
+	oidc.GET("/start", controller.Start)
+	oidc.GET("/callback", controller.Callback)
+}
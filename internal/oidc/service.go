@@ -0,0 +1,238 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"task-management/internal/auth"
+	"task-management/internal/config"
+	"task-management/internal/session"
+	"task-management/internal/user"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// an authorization request has to come back to /callback within this window
+const stateTTL = 10 * time.Minute
+
+type Service interface {
+	// Start returns the provider URL the browser should be sent to
+	Start(ctx context.Context, providerName string) (string, error)
+	Callback(ctx context.Context, providerName string, state string, code string, client *session.ClientInfo) (*auth.AuthResponse, error)
+}
+
+// pendingLogin is what we remember in Redis between /start and /callback
+type pendingLogin struct {
+	Provider string `json:"provider"`
+	Verifier string `json:"verifier"`
+	Nonce    string `json:"nonce"`
+}
+
+// identityStore and userStore are the parts of the repositories the login
+// needs; tests provide them without a database
+type identityStore interface {
+	FindIdentity(ctx context.Context, provider string, subject string) (*ExternalIdentity, error)
+	CreateIdentity(ctx context.Context, identity *ExternalIdentity) error
+}
+
+type userStore interface {
+	FindByID(ctx context.Context, id uuid.UUID) (*user.User, error)
+	FindByEmail(ctx context.Context, email string) (*user.User, error)
+	Create(ctx context.Context, user *user.User) error
+}
+
+type service struct {
+	repo        identityStore
+	userRepo    userStore
+	authService auth.AuthService
+	rdb         *redis.Client
+	providers   map[string]*provider
+}
+
+func NewService(config *config.Config, repo *Repository, userRepo *user.Repository, authService auth.AuthService, rdb *redis.Client) Service {
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	providers := map[string]*provider{}
+	for name, providerConfig := range config.OIDC.Providers {
+		providers[name] = newProvider(name, providerConfig, client)
+	}
+
+	return &service{
+		repo:        repo,
+		userRepo:    userRepo,
+		authService: authService,
+		rdb:         rdb,
+		providers:   providers,
+	}
+}
+
+func stateKey(state string) string {
+	return "oidc:state:" + state
+}
+
+func randomString(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func (s *service) Start(ctx context.Context, providerName string) (string, error) {
+	p, ok := s.providers[providerName]
+	if !ok {
+		return "", fmt.Errorf("unknown provider")
+	}
+
+	state, err := randomString(24)
+	if err != nil {
+		return "", fmt.Errorf("Start: %v", err)
+	}
+	nonce, err := randomString(24)
+	if err != nil {
+		return "", fmt.Errorf("Start: %v", err)
+	}
+	verifier, err := randomString(32)
+	if err != nil {
+		return "", fmt.Errorf("Start: %v", err)
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	challenge := base64.RawURLEncoding.EncodeToString(sum[:])
+
+	authURL, err := p.authCodeURL(ctx, state, nonce, challenge)
+	if err != nil {
+		return "", fmt.Errorf("Start: %v", err)
+	}
+
+	pending, err := json.Marshal(&pendingLogin{Provider: providerName, Verifier: verifier, Nonce: nonce})
+	if err != nil {
+		return "", fmt.Errorf("Start: %v", err)
+	}
+	if err := s.rdb.Set(ctx, stateKey(state), pending, stateTTL).Err(); err != nil {
+		return "", fmt.Errorf("Start: %v", err)
+	}
+
+	return authURL, nil
+}
+
+func (s *service) Callback(ctx context.Context, providerName string, state string, code string, client *session.ClientInfo) (*auth.AuthResponse, error) {
+	p, ok := s.providers[providerName]
+	if !ok {
+		return nil, fmt.Errorf("unknown provider")
+	}
+	if state == "" || code == "" {
+		return nil, fmt.Errorf("invalid or expired state")
+	}
+
+	// GETDEL makes every state single-use
+	raw, err := s.rdb.GetDel(ctx, stateKey(state)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, fmt.Errorf("invalid or expired state")
+		}
+		return nil, fmt.Errorf("Callback: %v", err)
+	}
+
+	var pending pendingLogin
+	if err := json.Unmarshal([]byte(raw), &pending); err != nil || pending.Provider != providerName {
+		return nil, fmt.Errorf("invalid or expired state")
+	}
+
+	tokens, err := p.exchange(ctx, code, pending.Verifier)
+	if err != nil {
+		return nil, fmt.Errorf("provider login failed - Callback: %v", err)
+	}
+
+	claims, err := p.verifyIDToken(ctx, tokens.IDToken, pending.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("provider login failed - Callback: %v", err)
+	}
+
+	userInfo, err := s.resolveUser(ctx, providerName, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.authService.LoginExternal(ctx, userInfo, client)
+}
+
+// resolveUser finds the local user for an external identity. Unknown
+// identities are linked to the account with the same email, or get a new
+// account, but only when the provider vouches for the email and the local
+// account has verified it too.
+func (s *service) resolveUser(ctx context.Context, providerName string, claims *IDTokenClaims) (*user.User, error) {
+	identity, err := s.repo.FindIdentity(ctx, providerName, claims.Subject)
+	if err != nil {
+		return nil, fmt.Errorf("resolveUser: %v", err)
+	}
+	if identity != nil {
+		userInfo, err := s.userRepo.FindByID(ctx, identity.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("resolveUser: %v", err)
+		}
+		return userInfo, nil
+	}
+
+	email := strings.TrimSpace(claims.Email)
+	if email == "" || !claims.IsEmailVerified() {
+		return nil, fmt.Errorf("email not verified by provider")
+	}
+
+	userInfo, err := s.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		if err.Error() != "user not found" {
+			return nil, fmt.Errorf("resolveUser: %v", err)
+		}
+		userInfo, err = s.createUser(ctx, email, claims.Name)
+		if err != nil {
+			return nil, err
+		}
+	} else if userInfo.EmailVerifiedAt == nil {
+		// whoever registered the address never proved they own it and may
+		// still hold its password, so it must not gain the provider's identity
+		return nil, fmt.Errorf("local account email not verified")
+	}
+
+	err = s.repo.CreateIdentity(ctx, &ExternalIdentity{
+		UserID:    userInfo.ID,
+		Provider:  providerName,
+		Subject:   claims.Subject,
+		Email:     email,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("resolveUser: %v", err)
+	}
+
+	return userInfo, nil
+}
+
+// createUser provisions an account without a password; the user can set one
+// later through the password reset flow
+func (s *service) createUser(ctx context.Context, email string, name string) (*user.User, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = strings.SplitN(email, "@", 2)[0]
+	}
+
+	now := time.Now()
+	new := &user.User{
+		Name:            name,
+		Email:           email,
+		EmailVerifiedAt: &now,
+		CreatedAt:       now,
+	}
+	if err := s.userRepo.Create(ctx, new); err != nil {
+		return nil, fmt.Errorf("failed to add user to DB - createUser: %v", err)
+	}
+	return new, nil
+}
@@ -0,0 +1,549 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"task-management/internal/auth"
+	"task-management/internal/config"
+	"task-management/internal/session"
+	"task-management/internal/testutil"
+	"task-management/internal/user"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	testClientID     = "task-management"
+	testClientSecret = "secret"
+	testRedirectURL  = "http://localhost:8080/auth/oidc/mock/callback"
+)
+
+// mockProvider is a minimal OpenID provider: discovery, JWKS and a token
+// endpoint that checks PKCE. Tests play the user's part at the authorization
+// endpoint by calling approve.
+type mockProvider struct {
+	t      *testing.T
+	srv    *httptest.Server
+	key    *rsa.PrivateKey
+	kid    string
+	issuer string
+
+	mu             sync.Mutex
+	grants         map[string]grant
+	discoveryCalls int
+	// editClaims changes the id_token claims before they are signed
+	editClaims func(claims jwt.MapClaims)
+	// signingKey, when set, signs id_tokens instead of the published key
+	signingKey *rsa.PrivateKey
+}
+
+type grant struct {
+	challenge string
+	nonce     string
+}
+
+func newMockProvider(t *testing.T) *mockProvider {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &mockProvider{t: t, key: key, kid: "k1", grants: map[string]grant{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", m.discovery)
+	mux.HandleFunc("/jwks", m.jwks)
+	mux.HandleFunc("/token", m.token)
+	m.srv = httptest.NewServer(mux)
+	m.issuer = m.srv.URL
+	t.Cleanup(m.srv.Close)
+	return m
+}
+
+func (m *mockProvider) discovery(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	m.discoveryCalls++
+	issuer := m.issuer
+	m.mu.Unlock()
+
+	json.NewEncoder(w).Encode(map[string]string{
+		"issuer":                 issuer,
+		"authorization_endpoint": m.srv.URL + "/authorize",
+		"token_endpoint":         m.srv.URL + "/token",
+		"jwks_uri":               m.srv.URL + "/jwks",
+	})
+}
+
+func (m *mockProvider) jwks(w http.ResponseWriter, r *http.Request) {
+	public := m.key.PublicKey
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": m.kid,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+		}},
+	})
+}
+
+func (m *mockProvider) token(w http.ResponseWriter, r *http.Request) {
+	fail := func(reason string) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant", "error_description": reason})
+	}
+
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok || clientID != testClientID || clientSecret != testClientSecret {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		fail(err.Error())
+		return
+	}
+	if r.PostForm.Get("grant_type") != "authorization_code" || r.PostForm.Get("redirect_uri") != testRedirectURL {
+		fail("bad grant_type or redirect_uri")
+		return
+	}
+
+	m.mu.Lock()
+	g, ok := m.grants[r.PostForm.Get("code")]
+	delete(m.grants, r.PostForm.Get("code"))
+	m.mu.Unlock()
+	if !ok {
+		fail("unknown code")
+		return
+	}
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(sum[:]) != g.challenge {
+		fail("code_verifier does not match code_challenge")
+		return
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":            m.issuer,
+		"aud":            testClientID,
+		"sub":            "subject-1",
+		"email":          "ada@example.com",
+		"email_verified": true,
+		"name":           "Ada",
+		"nonce":          g.nonce,
+		"iat":            now.Unix(),
+		"exp":            now.Add(5 * time.Minute).Unix(),
+	}
+	if m.editClaims != nil {
+		m.editClaims(claims)
+	}
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	idToken.Header["kid"] = m.kid
+	signingKey := m.key
+	if m.signingKey != nil {
+		signingKey = m.signingKey
+	}
+	signed, err := idToken.SignedString(signingKey)
+	if err != nil {
+		m.t.Errorf("sign id_token: %v", err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{
+		"access_token": "access",
+		"token_type":   "Bearer",
+		"id_token":     signed,
+	})
+}
+
+// approve plays the user consenting at the authorization endpoint and
+// returns the code and state the browser would bring back to /callback
+func (m *mockProvider) approve(authURL string) (code string, state string) {
+	m.t.Helper()
+	u, err := url.Parse(authURL)
+	if err != nil {
+		m.t.Fatal(err)
+	}
+	if !strings.HasPrefix(authURL, m.srv.URL+"/authorize?") {
+		m.t.Fatalf("redirected to %s", authURL)
+	}
+	q := u.Query()
+	if q.Get("response_type") != "code" || q.Get("client_id") != testClientID || q.Get("redirect_uri") != testRedirectURL {
+		m.t.Fatalf("unexpected authorization request %s", authURL)
+	}
+	if q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "" || q.Get("nonce") == "" {
+		m.t.Fatalf("authorization request without PKCE or nonce: %s", authURL)
+	}
+
+	code = uuid.NewString()
+	m.mu.Lock()
+	m.grants[code] = grant{challenge: q.Get("code_challenge"), nonce: q.Get("nonce")}
+	m.mu.Unlock()
+	return code, q.Get("state")
+}
+
+func (m *mockProvider) providerConfig() config.OIDCProviderConfig {
+	return config.OIDCProviderConfig{
+		Issuer:       m.srv.URL,
+		ClientID:     testClientID,
+		ClientSecret: testClientSecret,
+		RedirectURL:  testRedirectURL,
+		Scopes:       []string{"openid", "email", "profile"},
+	}
+}
+
+type fakeIdentities struct {
+	identities []*ExternalIdentity
+}
+
+func (f *fakeIdentities) FindIdentity(ctx context.Context, provider string, subject string) (*ExternalIdentity, error) {
+	for _, identity := range f.identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			return identity, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeIdentities) CreateIdentity(ctx context.Context, identity *ExternalIdentity) error {
+	f.identities = append(f.identities, identity)
+	return nil
+}
+
+type fakeUsers struct {
+	users []*user.User
+}
+
+func (f *fakeUsers) FindByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
+	for _, u := range f.users {
+		if u.ID == id {
+			return u, nil
+		}
+	}
+	return nil, fmt.Errorf("user not found")
+}
+
+func (f *fakeUsers) FindByEmail(ctx context.Context, email string) (*user.User, error) {
+	for _, u := range f.users {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+	return nil, fmt.Errorf("user not found")
+}
+
+func (f *fakeUsers) Create(ctx context.Context, u *user.User) error {
+	u.ID = uuid.New()
+	f.users = append(f.users, u)
+	return nil
+}
+
+// fakeAuth records who LoginExternal was asked to log in
+type fakeAuth struct {
+	auth.AuthService
+	loggedIn *user.User
+}
+
+func (f *fakeAuth) LoginExternal(ctx context.Context, userInfo *user.User, client *session.ClientInfo) (*auth.AuthResponse, error) {
+	f.loggedIn = userInfo
+	return &auth.AuthResponse{Token: "token-for-" + userInfo.ID.String()}, nil
+}
+
+type harness struct {
+	mock       *mockProvider
+	service    *service
+	users      *fakeUsers
+	identities *fakeIdentities
+	auth       *fakeAuth
+}
+
+func newHarness(t *testing.T) *harness {
+	mock := newMockProvider(t)
+	h := &harness{mock: mock, users: &fakeUsers{}, identities: &fakeIdentities{}, auth: &fakeAuth{}}
+	h.service = &service{
+		repo:        h.identities,
+		userRepo:    h.users,
+		authService: h.auth,
+		rdb:         testutil.NewRedis(),
+		providers: map[string]*provider{
+			"mock":  newProvider("mock", mock.providerConfig(), mock.srv.Client()),
+			"other": newProvider("other", mock.providerConfig(), mock.srv.Client()),
+		},
+	}
+	return h
+}
+
+// login runs /start, the user's approval and /callback
+func (h *harness) login(t *testing.T) (*auth.AuthResponse, error) {
+	t.Helper()
+	ctx := context.Background()
+	authURL, err := h.service.Start(ctx, "mock")
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	code, state := h.mock.approve(authURL)
+	return h.service.Callback(ctx, "mock", state, code, &session.ClientInfo{})
+}
+
+func TestDiscoveryIsCached(t *testing.T) {
+	mock := newMockProvider(t)
+	p := newProvider("mock", mock.providerConfig(), mock.srv.Client())
+
+	for i := 0; i < 2; i++ {
+		meta, err := p.discover(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if meta.TokenEndpoint != mock.srv.URL+"/token" || meta.JWKSURI != mock.srv.URL+"/jwks" {
+			t.Fatalf("unexpected discovery document %+v", meta)
+		}
+	}
+	if mock.discoveryCalls != 1 {
+		t.Errorf("fetched discovery %d times, want 1", mock.discoveryCalls)
+	}
+}
+
+func TestDiscoveryRejectsIssuerMismatch(t *testing.T) {
+	mock := newMockProvider(t)
+	mock.issuer = "https://evil.example.com"
+	p := newProvider("mock", mock.providerConfig(), mock.srv.Client())
+
+	_, err := p.discover(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "issuer mismatch") {
+		t.Fatalf("got %v, want issuer mismatch", err)
+	}
+}
+
+func TestLoginCreatesAccountForNewIdentity(t *testing.T) {
+	h := newHarness(t)
+
+	res, err := h.login(t)
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	if len(h.users.users) != 1 {
+		t.Fatalf("created %d users, want 1", len(h.users.users))
+	}
+	created := h.users.users[0]
+	if created.Email != "ada@example.com" || created.Name != "Ada" || created.EmailVerifiedAt == nil {
+		t.Errorf("unexpected account %+v", created)
+	}
+	if created.PasswordHash != "" {
+		t.Error("provisioned account has a password")
+	}
+	if len(h.identities.identities) != 1 || h.identities.identities[0].UserID != created.ID {
+		t.Fatalf("identity not linked to the new account: %+v", h.identities.identities)
+	}
+	if res.Token != "token-for-"+created.ID.String() {
+		t.Errorf("logged in as %q", res.Token)
+	}
+}
+
+func TestExchangeRequiresMatchingCodeVerifier(t *testing.T) {
+	h := newHarness(t)
+	authURL, err := h.service.Start(context.Background(), "mock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, _ := h.mock.approve(authURL)
+
+	_, err = h.service.providers["mock"].exchange(context.Background(), code, "not-the-verifier")
+	if err == nil || !strings.Contains(err.Error(), "code_verifier") {
+		t.Fatalf("got %v, want the provider to reject the verifier", err)
+	}
+}
+
+func TestCallbackRejectsInvalidIDTokens(t *testing.T) {
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		edit       func(claims jwt.MapClaims)
+		signingKey *rsa.PrivateKey
+	}{
+		{name: "nonce from another login", edit: func(c jwt.MapClaims) { c["nonce"] = "replayed" }},
+		{name: "missing nonce", edit: func(c jwt.MapClaims) { delete(c, "nonce") }},
+		{name: "wrong issuer", edit: func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" }},
+		{name: "wrong audience", edit: func(c jwt.MapClaims) { c["aud"] = "someone-else" }},
+		{name: "expired", edit: func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() }},
+		{name: "missing subject", edit: func(c jwt.MapClaims) { delete(c, "sub") }},
+		{name: "signed with an unpublished key", signingKey: otherKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHarness(t)
+			h.mock.editClaims = tt.edit
+			h.mock.signingKey = tt.signingKey
+
+			_, err := h.login(t)
+			if err == nil || !strings.Contains(err.Error(), "provider login failed") {
+				t.Fatalf("got %v, want provider login failed", err)
+			}
+			if len(h.users.users) != 0 || len(h.identities.identities) != 0 || h.auth.loggedIn != nil {
+				t.Error("a rejected id_token still logged someone in")
+			}
+		})
+	}
+}
+
+func TestStateIsSingleUse(t *testing.T) {
+	h := newHarness(t)
+	ctx := context.Background()
+
+	authURL, err := h.service.Start(ctx, "mock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, state := h.mock.approve(authURL)
+	if _, err := h.service.Callback(ctx, "mock", state, code, &session.ClientInfo{}); err != nil {
+		t.Fatalf("first callback: %v", err)
+	}
+
+	code, _ = h.mock.approve(authURL)
+	_, err = h.service.Callback(ctx, "mock", state, code, &session.ClientInfo{})
+	if err == nil || err.Error() != "invalid or expired state" {
+		t.Fatalf("reused state: got %v", err)
+	}
+}
+
+func TestStateIsBoundToProvider(t *testing.T) {
+	h := newHarness(t)
+	ctx := context.Background()
+
+	authURL, err := h.service.Start(ctx, "mock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, state := h.mock.approve(authURL)
+
+	_, err = h.service.Callback(ctx, "other", state, code, &session.ClientInfo{})
+	if err == nil || err.Error() != "invalid or expired state" {
+		t.Fatalf("state used at another provider: got %v", err)
+	}
+	_, err = h.service.Callback(ctx, "mock", "unknown-state", code, &session.ClientInfo{})
+	if err == nil || err.Error() != "invalid or expired state" {
+		t.Fatalf("unknown state: got %v", err)
+	}
+}
+
+func TestLinkByEmail(t *testing.T) {
+	verifiedAt := time.Now().Add(-24 * time.Hour)
+
+	tests := []struct {
+		name          string
+		local         *user.User
+		edit          func(claims jwt.MapClaims)
+		wantErr       string
+		wantLinked    bool
+		wantUserCount int
+	}{
+		{
+			name:          "verified local account is linked",
+			local:         &user.User{ID: uuid.New(), Email: "ada@example.com", PasswordHash: "hash", EmailVerifiedAt: &verifiedAt},
+			wantLinked:    true,
+			wantUserCount: 1,
+		},
+		{
+			name:          "unverified local account is not linked",
+			local:         &user.User{ID: uuid.New(), Email: "ada@example.com", PasswordHash: "attacker-chosen"},
+			wantErr:       "local account email not verified",
+			wantUserCount: 1,
+		},
+		{
+			name:          "email the provider has not verified",
+			local:         &user.User{ID: uuid.New(), Email: "ada@example.com", PasswordHash: "hash", EmailVerifiedAt: &verifiedAt},
+			edit:          func(c jwt.MapClaims) { c["email_verified"] = false },
+			wantErr:       "email not verified by provider",
+			wantUserCount: 1,
+		},
+		{
+			name:          "email_verified sent as a string",
+			local:         &user.User{ID: uuid.New(), Email: "ada@example.com", PasswordHash: "hash", EmailVerifiedAt: &verifiedAt},
+			edit:          func(c jwt.MapClaims) { c["email_verified"] = "true" },
+			wantLinked:    true,
+			wantUserCount: 1,
+		},
+		{
+			name:          "no email claim",
+			edit:          func(c jwt.MapClaims) { delete(c, "email") },
+			wantErr:       "email not verified by provider",
+			wantUserCount: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHarness(t)
+			if tt.local != nil {
+				h.users.users = append(h.users.users, tt.local)
+			}
+			h.mock.editClaims = tt.edit
+
+			_, err := h.login(t)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("got %v, want %s", err, tt.wantErr)
+				}
+				if len(h.identities.identities) != 0 || h.auth.loggedIn != nil {
+					t.Error("identity linked or user logged in despite the error")
+				}
+			} else if err != nil {
+				t.Fatalf("login: %v", err)
+			}
+
+			if len(h.users.users) != tt.wantUserCount {
+				t.Errorf("%d users, want %d", len(h.users.users), tt.wantUserCount)
+			}
+			if tt.wantLinked {
+				if len(h.identities.identities) != 1 || h.identities.identities[0].UserID != tt.local.ID {
+					t.Fatalf("identity not linked to the local account: %+v", h.identities.identities)
+				}
+				if h.auth.loggedIn != tt.local {
+					t.Error("did not log into the local account")
+				}
+			}
+		})
+	}
+}
+
+func TestReturningIdentityLogsIntoLinkedAccount(t *testing.T) {
+	h := newHarness(t)
+	verifiedAt := time.Now()
+	linked := &user.User{ID: uuid.New(), Email: "old@example.com", EmailVerifiedAt: &verifiedAt}
+	h.users.users = append(h.users.users, linked)
+	h.identities.identities = append(h.identities.identities, &ExternalIdentity{
+		UserID: linked.ID, Provider: "mock", Subject: "subject-1", Email: "old@example.com",
+	})
+	// the provider now reports another, unverified address; the subject is what counts
+	h.mock.editClaims = func(c jwt.MapClaims) {
+		c["email"] = "new@example.com"
+		c["email_verified"] = false
+	}
+
+	if _, err := h.login(t); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	if h.auth.loggedIn != linked {
+		t.Fatal("did not log into the linked account")
+	}
+	if len(h.users.users) != 1 || len(h.identities.identities) != 1 {
+		t.Error("a returning identity created accounts or identities")
+	}
+}
@@ -0,0 +1,172 @@
+package password
+
+import (
+	"strings"
+	"testing"
+
+	"task-management/internal/config"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// testConfig keeps the work factors low so the tests run quickly
+func testConfig(algorithm string) *config.Config {
+	return &config.Config{Password: config.PasswordConfig{
+		HashAlgorithm:     algorithm,
+		Argon2MemoryKiB:   1024,
+		Argon2Iterations:  1,
+		Argon2Parallelism: 1,
+		BcryptCost:        bcrypt.MinCost,
+		MinLength:         10,
+		DenyCommon:        true,
+	}}
+}
+
+func newTestHasher(t *testing.T, cfg *config.Config) Hasher {
+	t.Helper()
+	h, err := NewHasher(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+func TestHashRoundTrip(t *testing.T) {
+	for _, algorithm := range []string{AlgorithmArgon2id, AlgorithmBcrypt} {
+		t.Run(algorithm, func(t *testing.T) {
+			h := newTestHasher(t, testConfig(algorithm))
+
+			encoded, err := h.Hash("correct horse battery staple")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !h.Verify("correct horse battery staple", encoded) {
+				t.Error("the right password was rejected")
+			}
+			if h.Verify("correct horse battery stapler", encoded) {
+				t.Error("a wrong password was accepted")
+			}
+			if h.NeedsRehash(encoded) {
+				t.Error("a fresh hash needs rehashing")
+			}
+		})
+	}
+}
+
+func TestArgon2HashFormat(t *testing.T) {
+	h := newTestHasher(t, testConfig(AlgorithmArgon2id))
+
+	first, err := h.Hash("same password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := h.Hash("same password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(first, "$argon2id$v=19$m=1024,t=1,p=1$") {
+		t.Errorf("unexpected encoding %s", first)
+	}
+	if first == second {
+		t.Error("two hashes of one password share a salt")
+	}
+}
+
+func TestVerifyUnderstandsEveryFormat(t *testing.T) {
+	argon := newTestHasher(t, testConfig(AlgorithmArgon2id))
+	legacy := newTestHasher(t, testConfig(AlgorithmBcrypt))
+
+	bcryptHash, err := legacy.Hash("hunter2hunter2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !argon.Verify("hunter2hunter2", bcryptHash) {
+		t.Error("an argon2id hasher rejected a legacy bcrypt hash")
+	}
+
+	argonHash, err := argon.Hash("hunter2hunter2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !legacy.Verify("hunter2hunter2", argonHash) {
+		t.Error("a bcrypt hasher rejected an argon2id hash")
+	}
+}
+
+func TestVerifyRejectsMissingOrMalformedHashes(t *testing.T) {
+	h := newTestHasher(t, testConfig(AlgorithmArgon2id))
+	for _, encoded := range []string{
+		"",
+		"plaintext",
+		"$argon2id$v=19$m=1024,t=1,p=1$c2FsdA",
+		"$argon2id$v=16$m=1024,t=1,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=1024,t=1,p=1$!!!$a2V5",
+		"$2b$04$short",
+	} {
+		if h.Verify("", encoded) || h.Verify("password", encoded) {
+			t.Errorf("accepted %q", encoded)
+		}
+	}
+}
+
+func TestNeedsRehash(t *testing.T) {
+	argon := newTestHasher(t, testConfig(AlgorithmArgon2id))
+
+	stronger := testConfig(AlgorithmArgon2id)
+	stronger.Password.Argon2Iterations = 2
+	strongerArgon := newTestHasher(t, stronger)
+
+	legacy := newTestHasher(t, testConfig(AlgorithmBcrypt))
+	costlier := testConfig(AlgorithmBcrypt)
+	costlier.Password.BcryptCost = bcrypt.MinCost + 1
+	costlierBcrypt := newTestHasher(t, costlier)
+
+	argonHash, _ := argon.Hash("a long enough password")
+	bcryptHash, _ := legacy.Hash("a long enough password")
+
+	tests := []struct {
+		name    string
+		hasher  Hasher
+		encoded string
+		want    bool
+	}{
+		{"argon2id with current parameters", argon, argonHash, false},
+		{"argon2id with older parameters", strongerArgon, argonHash, true},
+		{"legacy bcrypt under argon2id", argon, bcryptHash, true},
+		{"bcrypt with current cost", legacy, bcryptHash, false},
+		{"bcrypt with a lower cost", costlierBcrypt, bcryptHash, true},
+		{"argon2id under bcrypt", legacy, argonHash, true},
+		{"no password", argon, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.hasher.NeedsRehash(tt.encoded); got != tt.want {
+				t.Errorf("NeedsRehash = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewHasherRejectsBadConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		edit func(cfg *config.PasswordConfig)
+	}{
+		{"empty algorithm", func(c *config.PasswordConfig) { c.HashAlgorithm = "" }},
+		{"unknown algorithm", func(c *config.PasswordConfig) { c.HashAlgorithm = "md5" }},
+		{"no iterations", func(c *config.PasswordConfig) { c.Argon2Iterations = 0 }},
+		{"too little memory", func(c *config.PasswordConfig) { c.Argon2MemoryKiB = 4 }},
+		{"no parallelism", func(c *config.PasswordConfig) { c.Argon2Parallelism = 0 }},
+		{"bcrypt cost too low", func(c *config.PasswordConfig) { c.BcryptCost = bcrypt.MinCost - 1 }},
+		{"bcrypt cost too high", func(c *config.PasswordConfig) { c.BcryptCost = bcrypt.MaxCost + 1 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig(AlgorithmArgon2id)
+			tt.edit(&cfg.Password)
+			if _, err := NewHasher(cfg); err == nil {
+				t.Error("accepted")
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package password
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"task-management/internal/config"
+)
+
+// common_passwords.txt holds well-known leaked passwords, one per line
+//
+//go:embed common_passwords.txt
+var commonPasswordsFile []byte
+
+// PolicyError explains why a password was rejected
+type PolicyError struct {
+	Reason string
+}
+
+func (e *PolicyError) Error() string {
+	return "password rejected: " + e.Reason
+}
+
+// bcryptMaxBytes is the most bcrypt will hash; it refuses longer input
+const bcryptMaxBytes = 72
+
+type Policy struct {
+	minLength int
+	// maxBytes is 0 when the hasher takes passwords of any length
+	maxBytes int
+	denied   map[string]struct{}
+}
+
+func NewPolicy(config *config.Config) *Policy {
+	policy := &Policy{minLength: config.Password.MinLength, denied: map[string]struct{}{}}
+	if config.Password.HashAlgorithm == AlgorithmBcrypt {
+		policy.maxBytes = bcryptMaxBytes
+	}
+	if !config.Password.DenyCommon {
+		return policy
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(commonPasswordsFile))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		policy.denied[strings.ToLower(line)] = struct{}{}
+	}
+	return policy
+}
+
+// Validate returns a *PolicyError when the password is not acceptable
+func (p *Policy) Validate(password string) error {
+	if utf8.RuneCountInString(password) < p.minLength {
+		return &PolicyError{Reason: fmt.Sprintf("must be at least %d characters long", p.minLength)}
+	}
+	if p.maxBytes > 0 && len(password) > p.maxBytes {
+		return &PolicyError{Reason: fmt.Sprintf("must be at most %d bytes long", p.maxBytes)}
+	}
+	if strings.TrimSpace(password) == "" {
+		return &PolicyError{Reason: "must not be blank"}
+	}
+	if _, ok := p.denied[strings.ToLower(password)]; ok {
+		return &PolicyError{Reason: "too common, choose a less predictable password"}
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package password
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPolicyValidate(t *testing.T) {
+	policy := NewPolicy(testConfig(AlgorithmArgon2id))
+
+	tests := []struct {
+		name     string
+		password string
+		reason   string
+	}{
+		{"long and uncommon", "violet-anchor-42-drift", ""},
+		{"exactly the minimum length", "zq8!mv2#kp", ""},
+		{"one character short", "zq8!mv2#k", "at least 10 characters"},
+		{"length counts characters, not bytes", "ééééééééé", "at least 10 characters"},
+		{"multibyte at the minimum", "éééééééééé", ""},
+		{"blank", "            ", "must not be blank"},
+		{"common password", "qwertyuiop", "too common"},
+		{"common password in another case", "PassWord123", "too common"},
+		{"common password with a symbol", "password1!", "too common"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Validate(tt.password)
+			if tt.reason == "" {
+				if err != nil {
+					t.Fatalf("rejected: %v", err)
+				}
+				return
+			}
+
+			var policyErr *PolicyError
+			if !errors.As(err, &policyErr) {
+				t.Fatalf("got %v, want a *PolicyError", err)
+			}
+			if !strings.Contains(policyErr.Reason, tt.reason) {
+				t.Errorf("reason %q, want it to mention %q", policyErr.Reason, tt.reason)
+			}
+		})
+	}
+}
+
+func TestPolicyWithoutDenyList(t *testing.T) {
+	cfg := testConfig(AlgorithmArgon2id)
+	cfg.Password.DenyCommon = false
+	policy := NewPolicy(cfg)
+
+	if err := policy.Validate("qwertyuiop"); err != nil {
+		t.Errorf("common passwords are allowed when the deny list is off: %v", err)
+	}
+	if err := policy.Validate("short"); err == nil {
+		t.Error("the minimum length still applies")
+	}
+}
+
+func TestPolicyBcryptLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		algorithm string
+		password  string
+		rejected  bool
+	}{
+		{"72 bytes under bcrypt", AlgorithmBcrypt, strings.Repeat("k", 72), false},
+		{"73 bytes under bcrypt", AlgorithmBcrypt, strings.Repeat("k", 73), true},
+		{"36 two-byte characters under bcrypt", AlgorithmBcrypt, strings.Repeat("é", 36), false},
+		{"37 two-byte characters under bcrypt", AlgorithmBcrypt, strings.Repeat("é", 37), true},
+		{"255 bytes under argon2id", AlgorithmArgon2id, strings.Repeat("k", 255), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := NewPolicy(testConfig(tt.algorithm))
+			err := policy.Validate(tt.password)
+			if !tt.rejected {
+				if err != nil {
+					t.Fatalf("rejected: %v", err)
+				}
+				return
+			}
+
+			var policyErr *PolicyError
+			if !errors.As(err, &policyErr) || !strings.Contains(policyErr.Reason, "at most 72 bytes") {
+				t.Fatalf("got %v, want a *PolicyError about the length", err)
+			}
+
+			// what the policy lets through, bcrypt can hash
+			if _, err := newTestHasher(t, testConfig(tt.algorithm)).Hash(tt.password[:72]); err != nil {
+				t.Errorf("bcrypt refused 72 bytes: %v", err)
+			}
+		})
+	}
+}
+
+func TestDenyListIsLoaded(t *testing.T) {
+	policy := NewPolicy(testConfig(AlgorithmArgon2id))
+	if len(policy.denied) < 100 {
+		t.Fatalf("only %d common passwords loaded", len(policy.denied))
+	}
+	for entry := range policy.denied {
+		if strings.HasPrefix(entry, "#") || strings.TrimSpace(entry) != entry || entry == "" {
+			t.Errorf("deny list entry %q was not cleaned up", entry)
+		}
+	}
+}
@@ -27,7 +27,31 @@ func (controller *Controller) List(c *gin.Context) {
 
 	userUUID := userID.(uuid.UUID)
 
-	projects, err := controller.service.List(c.Request.Context(), userUUID)
+	filters := make(map[string]interface{})
+	if role := c.Query("role"); role != "" {
+		if role != RoleOwner && role != "member" {
+			c.IndentedJSON(400, gin.H{"error": "role must be owner or member"})
+			return
+		}
+		filters["role"] = role
+	}
+
+	if archived := c.Query("archived"); archived != "" {
+		parsed, err := strconv.ParseBool(archived)
+		if err != nil {
+			c.IndentedJSON(400, gin.H{"error": "archived must be true or false"})
+			return
+		}
+		filters["archived"] = parsed
+	}
+
+	sort := c.Query("sort")
+	if sort != "" && sort != SortCreated && sort != SortName && sort != SortActivity {
+		c.IndentedJSON(400, gin.H{"error": "sort must be name, created or activity"})
+		return
+	}
+
+	projects, err := controller.service.List(c.Request.Context(), userUUID, filters, sort)
 	if err != nil {
 		c.IndentedJSON(500, gin.H{
 			"error": err.Error(),
@@ -58,6 +82,12 @@ func (controller *Controller) Create(c *gin.Context) {
 
 	project, err := controller.service.Create(c.Request.Context(), userUUID, &dto)
 	if err != nil {
+		if strings.Contains(err.Error(), "template not found") {
+			c.IndentedJSON(404, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
 		c.IndentedJSON(500, gin.H{
 			"error": err.Error(),
 		})
@@ -87,13 +117,13 @@ func (controller *Controller) GetByID(c *gin.Context) {
 
 	project, err := controller.service.GetByID(c.Request.Context(), projectID, userUUID)
 	if err != nil {
-		if err.Error() == "project not found" {
+		if strings.Contains(err.Error(), "project not found") {
 			c.IndentedJSON(404, gin.H{
 				"error": err.Error(),
 			})
 			return
 		}
-		if err.Error() == "unauthorized: you don't own this project" {
+		if strings.Contains(err.Error(), "unauthorized") {
 			c.IndentedJSON(403, gin.H{
 				"error": err.Error(),
 			})
@@ -132,18 +162,24 @@ func (controller *Controller) Update(c *gin.Context) {
 
 	project, err := controller.service.Update(c.Request.Context(), projectID, userUUID, &dto)
 	if err != nil {
-		if err.Error() == "project not found" {
+		if strings.Contains(err.Error(), "project not found") {
 			c.IndentedJSON(404, gin.H{
 				"error": err.Error(),
 			})
 			return
 		}
-		if err.Error() == "unauthorized: you don't own this project" {
+		if strings.Contains(err.Error(), "unauthorized") {
 			c.IndentedJSON(403, gin.H{
 				"error": err.Error(),
 			})
 			return
 		}
+		if strings.Contains(err.Error(), "archived") {
+			c.IndentedJSON(409, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
 		c.IndentedJSON(500, gin.H{
 			"error": err.Error(),
 		})
@@ -171,11 +207,11 @@ func (controller *Controller) Delete(c *gin.Context) {
 
 	err = controller.service.Delete(c.Request.Context(), projectID, userUUID)
 	if err != nil {
-		if err.Error() == "project not found" {
+		if strings.Contains(err.Error(), "project not found") {
 			c.IndentedJSON(404, gin.H{"error": err.Error()})
 			return
 		}
-		if err.Error() == "unauthorized: you don't own this project" {
+		if strings.Contains(err.Error(), "unauthorized") {
 			c.IndentedJSON(403, gin.H{"error": err.Error()})
 			return
 		}
@@ -190,12 +226,14 @@ func (controller *Controller) AddUser(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
 		c.IndentedJSON(401, gin.H{"error": "unauthorized"})
+		return
 	}
 	userUUID := userID.(uuid.UUID)
 
 	projectID, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
 		c.IndentedJSON(400, gin.H{"error": err.Error()})
+		return
 	}
 
 	var dto AddUserRequest
@@ -205,7 +243,7 @@ func (controller *Controller) AddUser(c *gin.Context) {
 		return
 	}
 
-	err = controller.service.AddUser(c.Request.Context(), projectID, userUUID, dto.UserID)
+	err = controller.service.AddUser(c.Request.Context(), projectID, userUUID, &dto)
 	if err != nil {
 		if strings.Contains(err.Error(), "unauthorized") {
 			c.IndentedJSON(403, gin.H{"error": err.Error()})
@@ -215,6 +253,18 @@ func (controller *Controller) AddUser(c *gin.Context) {
 			c.IndentedJSON(403, gin.H{"error": err.Error()})
 			return
 		}
+		if strings.Contains(err.Error(), "user not found") {
+			c.IndentedJSON(404, gin.H{"error": err.Error()})
+			return
+		}
+		if strings.Contains(err.Error(), "email not verified") {
+			c.IndentedJSON(422, gin.H{"error": err.Error()})
+			return
+		}
+		if strings.Contains(err.Error(), "member already added") {
+			c.IndentedJSON(409, gin.H{"error": err.Error()})
+			return
+		}
 
 		c.IndentedJSON(500, gin.H{"error": err.Error()})
 		return
@@ -223,3 +273,283 @@ func (controller *Controller) AddUser(c *gin.Context) {
 	c.IndentedJSON(201, gin.H{"message": "user added to project"})
 
 }
+
+func (controller *Controller) ListMembers(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.IndentedJSON(401, gin.H{"error": "unauthorized"})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+
+	projectID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.IndentedJSON(400, gin.H{"error": "invalid project id"})
+		return
+	}
+
+	members, err := controller.service.ListMembers(c.Request.Context(), projectID, userUUID)
+	if err != nil {
+		if strings.Contains(err.Error(), "project not found") {
+			c.IndentedJSON(404, gin.H{"error": err.Error()})
+			return
+		}
+		if strings.Contains(err.Error(), "unauthorized") {
+			c.IndentedJSON(403, gin.H{"error": err.Error()})
+			return
+		}
+		c.IndentedJSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.IndentedJSON(200, members)
+}
+
+func (controller *Controller) RemoveMember(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.IndentedJSON(401, gin.H{"error": "unauthorized"})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+
+	projectID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.IndentedJSON(400, gin.H{"error": "invalid project id"})
+		return
+	}
+
+	targetID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		c.IndentedJSON(400, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	err = controller.service.RemoveMember(c.Request.Context(), projectID, userUUID, targetID)
+	if err != nil {
+		if strings.Contains(err.Error(), "project not found") || strings.Contains(err.Error(), "member not found") {
+			c.IndentedJSON(404, gin.H{"error": err.Error()})
+			return
+		}
+		if strings.Contains(err.Error(), "unauthorized") {
+			c.IndentedJSON(403, gin.H{"error": err.Error()})
+			return
+		}
+		if strings.Contains(err.Error(), "owner cannot be removed") {
+			c.IndentedJSON(409, gin.H{"error": err.Error()})
+			return
+		}
+		c.IndentedJSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.IndentedJSON(200, gin.H{"message": "member removed from project"})
+}
+
+func (controller *Controller) Leave(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.IndentedJSON(401, gin.H{"error": "unauthorized"})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+
+	projectID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.IndentedJSON(400, gin.H{"error": "invalid project id"})
+		return
+	}
+
+	err = controller.service.Leave(c.Request.Context(), projectID, userUUID)
+	if err != nil {
+		if strings.Contains(err.Error(), "project not found") || strings.Contains(err.Error(), "member not found") {
+			c.IndentedJSON(404, gin.H{"error": err.Error()})
+			return
+		}
+		if strings.Contains(err.Error(), "owner cannot leave") {
+			c.IndentedJSON(409, gin.H{"error": err.Error()})
+			return
+		}
+		c.IndentedJSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.IndentedJSON(200, gin.H{"message": "you left the project"})
+}
+
+func (controller *Controller) UpdateMemberRole(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.IndentedJSON(401, gin.H{"error": "unauthorized"})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+
+	projectID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.IndentedJSON(400, gin.H{"error": "invalid project id"})
+		return
+	}
+
+	targetID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		c.IndentedJSON(400, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	var dto UpdateMemberRoleRequest
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.IndentedJSON(400, gin.H{"error": "invalid request body: " + err.Error()})
+		return
+	}
+
+	err = controller.service.UpdateMemberRole(c.Request.Context(), projectID, userUUID, targetID, &dto)
+	if err != nil {
+		if strings.Contains(err.Error(), "project not found") || strings.Contains(err.Error(), "member not found") {
+			c.IndentedJSON(404, gin.H{"error": err.Error()})
+			return
+		}
+		if strings.Contains(err.Error(), "unauthorized") {
+			c.IndentedJSON(403, gin.H{"error": err.Error()})
+			return
+		}
+		if strings.Contains(err.Error(), "owner cannot") {
+			c.IndentedJSON(409, gin.H{"error": err.Error()})
+			return
+		}
+		c.IndentedJSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.IndentedJSON(200, gin.H{"message": "member role updated", "role": dto.Role})
+}
+
+func (controller *Controller) Transfer(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.IndentedJSON(401, gin.H{"error": "unauthorized"})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+
+	projectID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.IndentedJSON(400, gin.H{"error": "invalid project id"})
+		return
+	}
+
+	var dto TransferProjectRequest
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.IndentedJSON(400, gin.H{"error": "invalid request body: " + err.Error()})
+		return
+	}
+
+	project, err := controller.service.Transfer(c.Request.Context(), projectID, userUUID, &dto)
+	if err != nil {
+		if strings.Contains(err.Error(), "project not found") || strings.Contains(err.Error(), "member not found") {
+			c.IndentedJSON(404, gin.H{"error": err.Error()})
+			return
+		}
+		if strings.Contains(err.Error(), "unauthorized") {
+			c.IndentedJSON(403, gin.H{"error": err.Error()})
+			return
+		}
+		if strings.Contains(err.Error(), "already own") {
+			c.IndentedJSON(409, gin.H{"error": err.Error()})
+			return
+		}
+		c.IndentedJSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.IndentedJSON(200, project)
+}
+
+func (controller *Controller) History(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.IndentedJSON(401, gin.H{"error": "unauthorized"})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+
+	projectID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.IndentedJSON(400, gin.H{"error": "invalid project id"})
+		return
+	}
+
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			c.IndentedJSON(400, gin.H{"error": "invalid limit"})
+			return
+		}
+		limit = parsed
+	}
+
+	events, err := controller.service.History(c.Request.Context(), projectID, userUUID, limit)
+	if err != nil {
+		if strings.Contains(err.Error(), "project not found") {
+			c.IndentedJSON(404, gin.H{"error": err.Error()})
+			return
+		}
+		if strings.Contains(err.Error(), "unauthorized") {
+			c.IndentedJSON(403, gin.H{"error": err.Error()})
+			return
+		}
+		c.IndentedJSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.IndentedJSON(200, events)
+}
+
+func (controller *Controller) Archive(c *gin.Context) {
+	controller.setArchived(c, true)
+}
+
+func (controller *Controller) Unarchive(c *gin.Context) {
+	controller.setArchived(c, false)
+}
+
+func (controller *Controller) setArchived(c *gin.Context, archived bool) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.IndentedJSON(401, gin.H{"error": "unauthorized"})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+
+	projectID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.IndentedJSON(400, gin.H{"error": "invalid project id"})
+		return
+	}
+
+	var project *ProjectResponse
+	if archived {
+		project, err = controller.service.Archive(c.Request.Context(), projectID, userUUID)
+	} else {
+		project, err = controller.service.Unarchive(c.Request.Context(), projectID, userUUID)
+	}
+	if err != nil {
+		if strings.Contains(err.Error(), "project not found") {
+			c.IndentedJSON(404, gin.H{"error": err.Error()})
+			return
+		}
+		if strings.Contains(err.Error(), "unauthorized") {
+			c.IndentedJSON(403, gin.H{"error": err.Error()})
+			return
+		}
+		if strings.Contains(err.Error(), "already archived") || strings.Contains(err.Error(), "not archived") {
+			c.IndentedJSON(409, gin.H{"error": err.Error()})
+			return
+		}
+		c.IndentedJSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.IndentedJSON(200, project)
+}
@@ -9,6 +9,10 @@ import (
 type CreateProjectRequest struct {
 	Name        string `json:"name" binding:"required,min=1,max=255"`
 	Description string `json:"description" binding:"omitempty,max=255"`
+	// TemplateID fills the new project with the template's tasks, their due
+	// dates counted from StartDate (default today)
+	TemplateID *int64     `json:"template_id" binding:"omitempty,min=1"`
+	StartDate  *time.Time `json:"start_date"`
 }
 
 type UpdateProjectRequest struct {
@@ -18,6 +22,26 @@ type UpdateProjectRequest struct {
 
 type AddUserRequest struct {
 	UserID uuid.UUID `json:"user_id" binding:"required,min=1,max=255"`
+	// Role defaults to editor
+	Role string `json:"role" binding:"omitempty,oneof=viewer editor maintainer admin"`
+}
+
+type UpdateMemberRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=viewer editor maintainer admin"`
+}
+
+type TransferProjectRequest struct {
+	UserID uuid.UUID `json:"user_id" binding:"required"`
+	// PreviousOwnerRole defaults to PROJECT_TRANSFER_PREVIOUS_OWNER_ROLE
+	PreviousOwnerRole string `json:"previous_owner_role" binding:"omitempty,oneof=viewer editor maintainer admin"`
+}
+
+type MemberResponse struct {
+	UserID uuid.UUID `json:"user_id"`
+	Name   string    `json:"name"`
+	Email  string    `json:"email"`
+	Handle *string   `json:"handle"`
+	Role   string    `json:"role"`
 }
 
 type ProjectResponse struct {
@@ -25,8 +49,14 @@ type ProjectResponse struct {
 	UserID      uuid.UUID `json:"user_id"`
 	Name        string    `json:"name"`
 	Description string    `json:"description"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	// Role is the caller's role in the project
+	Role string `json:"role,omitempty"`
+	// MemberCount includes the owner
+	MemberCount    int64      `json:"member_count,omitempty"`
+	LastActivityAt *time.Time `json:"last_activity_at,omitempty"`
+	ArchivedAt     *time.Time `json:"archived_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
 }
 
 func ToProjectResponse(project *Project) *ProjectResponse {
@@ -35,6 +65,7 @@ func ToProjectResponse(project *Project) *ProjectResponse {
 		UserID:      project.OwnerID,
 		Name:        project.Name,
 		Description: project.Description,
+		ArchivedAt:  project.ArchivedAt,
 		CreatedAt:   project.CreatedAt,
 		UpdatedAt:   project.UpdatedAt,
 	}
@@ -47,3 +78,19 @@ func ToProjectResponseList(projects []*Project) []*ProjectResponse {
 	}
 	return responses
 }
+
+func ToProjectSummaryResponse(summary *ProjectSummary) *ProjectResponse {
+	res := ToProjectResponse(&summary.Project)
+	res.Role = summary.Role
+	res.MemberCount = summary.MemberCount
+	res.LastActivityAt = &summary.LastActivityAt
+	return res
+}
+
+func ToProjectSummaryResponseList(summaries []*ProjectSummary) []*ProjectResponse {
+	responses := make([]*ProjectResponse, len(summaries))
+	for i, summary := range summaries {
+		responses[i] = ToProjectSummaryResponse(summary)
+	}
+	return responses
+}
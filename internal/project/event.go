@@ -0,0 +1,91 @@
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Project history event types
+const (
+	EventOwnershipTransferred = "ownership_transferred"
+	EventArchived             = "archived"
+	EventUnarchived           = "unarchived"
+	EventDeleted              = "deleted"
+	EventRestored             = "restored"
+	EventCloned               = "cloned"
+	EventCreatedFromTemplate  = "created_from_template"
+)
+
+type ProjectEvent struct {
+	ID        int64      `gorm:"primaryKey;autoIncrement"`
+	ProjectID int64      `gorm:"not null;index"`
+	ActorID   *uuid.UUID `gorm:"type:uuid"`
+	Type      string     `gorm:"type:varchar(50);not null"`
+	Details   string     `gorm:"type:jsonb;not null"`
+	CreatedAt time.Time  `gorm:"type:timestamp;not null"`
+}
+
+func (ProjectEvent) TableName() string {
+	return "project_event"
+}
+
+type ProjectEventResponse struct {
+	ID        int64           `json:"id"`
+	Type      string          `json:"type"`
+	ActorID   *uuid.UUID      `json:"actor_id"`
+	Details   json.RawMessage `json:"details"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+func ToProjectEventResponseList(events []*ProjectEvent) []*ProjectEventResponse {
+	responses := make([]*ProjectEventResponse, len(events))
+	for i, event := range events {
+		responses[i] = &ProjectEventResponse{
+			ID:        event.ID,
+			Type:      event.Type,
+			ActorID:   event.ActorID,
+			Details:   json.RawMessage(event.Details),
+			CreatedAt: event.CreatedAt,
+		}
+	}
+	return responses
+}
+
+// RecordEvent appends to the project's history inside tx, so the entry is
+// only kept if the change it describes is
+func RecordEvent(tx *gorm.DB, projectID int64, actorID uuid.UUID, eventType string, details map[string]interface{}) error {
+	if details == nil {
+		details = map[string]interface{}{}
+	}
+	encoded, err := json.Marshal(details)
+	if err != nil {
+		return fmt.Errorf("RecordEvent: %v", err)
+	}
+
+	event := &ProjectEvent{
+		ProjectID: projectID,
+		ActorID:   &actorID,
+		Type:      eventType,
+		Details:   string(encoded),
+		CreatedAt: time.Now(),
+	}
+	if err := tx.Create(event).Error; err != nil {
+		return fmt.Errorf("RecordEvent: %v", err)
+	}
+	return nil
+}
+
+func (r *Repository) ListEvents(ctx context.Context, projectID int64, limit int) ([]*ProjectEvent, error) {
+	var events []*ProjectEvent
+	err := r.db.WithContext(ctx).Where("project_id = ?", projectID).
+		Order("created_at DESC").Order("id DESC").Limit(limit).Find(&events).Error
+	if err != nil {
+		return nil, fmt.Errorf("ListEvents: %v", err)
+	}
+	return events, nil
+}
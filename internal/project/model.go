@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type Project struct {
@@ -13,16 +14,29 @@ type Project struct {
 	Description string    `gorm:"type:varchar(255)"`
 	CreatedAt   time.Time `gorm:"type:timestamp;not null"`
 	UpdatedAt   time.Time `gorm:"type:timestamp;not null"`
+	// ArchivedAt is set while the project is archived and read-only
+	ArchivedAt *time.Time `gorm:"type:timestamp"`
+	// DeletedAt puts the row in the trash; gorm leaves such rows out of queries
+	DeletedAt gorm.DeletedAt `gorm:"index"`
 }
 
 func (Project) TableName() string {
 	return "project"
 }
 
+// ProjectSummary is a project as seen by one user, with that user's role
+type ProjectSummary struct {
+	Project        `gorm:"embedded"`
+	Role           string
+	MemberCount    int64
+	LastActivityAt time.Time
+}
+
 type ProjectMember struct {
 	ID        int64     `gorm:"primaryKey;autoIncrement"`
 	ProjectID int64     `gorm:"column:project_id;not null"`
 	UserID    uuid.UUID `gorm:"column:user_id;type:uuid;not null"`
+	Role      string    `gorm:"type:varchar(20);not null;default:editor"`
 }
 
 func (ProjectMember) TableName() string {
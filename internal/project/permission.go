@@ -0,0 +1,163 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Roles a user can have in a project, lowest first. RoleOwner is implied by
+// project.owner_id and never stored on project_member.
+const (
+	RoleViewer     = "viewer"
+	RoleEditor     = "editor"
+	RoleMaintainer = "maintainer"
+	RoleAdmin      = "admin"
+	RoleOwner      = "owner"
+)
+
+var roleRank = map[string]int{
+	RoleViewer:     1,
+	RoleEditor:     2,
+	RoleMaintainer: 3,
+	RoleAdmin:      4,
+	RoleOwner:      5,
+}
+
+// Action is something a user can try to do inside a project
+type Action string
+
+const (
+	ActionView          Action = "view"
+	ActionCreateTask    Action = "create_task"
+	ActionEditTask      Action = "edit_task"
+	ActionDeleteTask    Action = "delete_task"
+	ActionEditSubtask   Action = "edit_subtask"
+	ActionDeleteSubtask Action = "delete_subtask"
+	ActionEditProject   Action = "edit_project"
+	ActionManageMembers Action = "manage_members"
+	ActionDeleteProject Action = "delete_project"
+	ActionTransfer      Action = "transfer_project"
+	ActionArchive       Action = "archive_project"
+	ActionComment       Action = "comment"
+	// ActionModerateComments is deleting other people's comments; comments
+	// are only ever edited by their author
+	ActionModerateComments Action = "moderate_comments"
+)
+
+// permissions is the single source of truth for who may do what: each action
+// lists the lowest role allowed to perform it
+var permissions = map[Action]string{
+	ActionView:             RoleViewer,
+	ActionCreateTask:       RoleEditor,
+	ActionEditTask:         RoleEditor,
+	ActionEditSubtask:      RoleEditor,
+	ActionDeleteTask:       RoleMaintainer,
+	ActionDeleteSubtask:    RoleMaintainer,
+	ActionEditProject:      RoleAdmin,
+	ActionManageMembers:    RoleAdmin,
+	ActionDeleteProject:    RoleOwner,
+	ActionTransfer:         RoleOwner,
+	ActionArchive:          RoleAdmin,
+	ActionComment:          RoleEditor,
+	ActionModerateComments: RoleAdmin,
+}
+
+// blockedWhenArchived are the actions that change a project's content. An
+// archived project stays readable and its membership can still be managed.
+var blockedWhenArchived = map[Action]bool{
+	ActionCreateTask:       true,
+	ActionEditTask:         true,
+	ActionDeleteTask:       true,
+	ActionEditSubtask:      true,
+	ActionDeleteSubtask:    true,
+	ActionEditProject:      true,
+	ActionComment:          true,
+	ActionModerateComments: true,
+}
+
+func IsValidRole(role string) bool {
+	_, ok := roleRank[role]
+	return ok && role != RoleOwner
+}
+
+// Can reports whether role is allowed to perform action
+func Can(role string, action Action) bool {
+	minRole, ok := permissions[action]
+	if !ok {
+		return false
+	}
+	return roleRank[role] >= roleRank[minRole]
+}
+
+// RolesAllowed lists the stored member roles that may perform action, for
+// queries that have to check permissions in SQL. The owner is not included.
+func RolesAllowed(action Action) []string {
+	roles := []string{}
+	for _, role := range []string{RoleViewer, RoleEditor, RoleMaintainer, RoleAdmin} {
+		if Can(role, action) {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+// Outranks reports whether a can manage someone with role b
+func Outranks(a string, b string) bool {
+	return roleRank[a] > roleRank[b]
+}
+
+// RoleOf returns the user's role in the project, or "" when they have no access
+func (r *Repository) RoleOf(ctx context.Context, project *Project, userID uuid.UUID) (string, error) {
+	if project.OwnerID == userID {
+		return RoleOwner, nil
+	}
+
+	var member ProjectMember
+	err := r.db.WithContext(ctx).Where("project_id = ? AND user_id = ?", project.ID, userID).First(&member).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", nil
+		}
+		return "", fmt.Errorf("RoleOf: %v", err)
+	}
+	return member.Role, nil
+}
+
+// Authorize loads the project and checks that the user may perform action
+// in it. It returns the project and the user's role on success. Archived
+// projects refuse every action in blockedWhenArchived.
+func (r *Repository) Authorize(ctx context.Context, projectID int64, userID uuid.UUID, action Action) (*Project, string, error) {
+	project, err := r.FindByID(ctx, projectID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	role, err := r.RoleOf(ctx, project, userID)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := checkAccess(project, role, action); err != nil {
+		return nil, "", err
+	}
+
+	return project, role, nil
+}
+
+// checkAccess is the decision Authorize makes once it knows the user's role
+func checkAccess(project *Project, role string, action Action) error {
+	if role == "" {
+		return fmt.Errorf("unauthorized: you don't have access to this project")
+	}
+	if !Can(role, action) {
+		return fmt.Errorf("unauthorized: %s role cannot %s", role, strings.ReplaceAll(string(action), "_", " "))
+	}
+	if project.ArchivedAt != nil && blockedWhenArchived[action] {
+		return fmt.Errorf("project is archived and read-only")
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package project
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+var allRoles = []string{RoleViewer, RoleEditor, RoleMaintainer, RoleAdmin, RoleOwner}
+
+// matrix lists, for every action, which of allRoles may perform it
+var matrix = []struct {
+	action          Action
+	allowed         [5]bool
+	blockedArchived bool
+}{
+	//                                 viewer editor maintainer admin owner
+	{ActionView, [5]bool{true, true, true, true, true}, false},
+	{ActionComment, [5]bool{false, true, true, true, true}, true},
+	{ActionCreateTask, [5]bool{false, true, true, true, true}, true},
+	{ActionEditTask, [5]bool{false, true, true, true, true}, true},
+	{ActionEditSubtask, [5]bool{false, true, true, true, true}, true},
+	{ActionDeleteTask, [5]bool{false, false, true, true, true}, true},
+	{ActionDeleteSubtask, [5]bool{false, false, true, true, true}, true},
+	{ActionEditProject, [5]bool{false, false, false, true, true}, true},
+	{ActionModerateComments, [5]bool{false, false, false, true, true}, true},
+	{ActionManageMembers, [5]bool{false, false, false, true, true}, false},
+	{ActionArchive, [5]bool{false, false, false, true, true}, false},
+	{ActionDeleteProject, [5]bool{false, false, false, false, true}, false},
+	{ActionTransfer, [5]bool{false, false, false, false, true}, false},
+}
+
+func TestMatrixCoversEveryAction(t *testing.T) {
+	covered := map[Action]bool{}
+	for _, row := range matrix {
+		covered[row.action] = true
+	}
+	for action := range permissions {
+		if !covered[action] {
+			t.Errorf("%s is missing from the test matrix", action)
+		}
+	}
+	for action := range blockedWhenArchived {
+		if !covered[action] {
+			t.Errorf("%s is blocked when archived but missing from the test matrix", action)
+		}
+	}
+}
+
+func TestCan(t *testing.T) {
+	for _, row := range matrix {
+		for i, role := range allRoles {
+			if got := Can(role, row.action); got != row.allowed[i] {
+				t.Errorf("Can(%s, %s) = %v, want %v", role, row.action, got, row.allowed[i])
+			}
+		}
+	}
+}
+
+func TestCanRejectsUnknownRolesAndActions(t *testing.T) {
+	if Can("", ActionView) {
+		t.Error("a non-member can view")
+	}
+	if Can("superuser", ActionView) {
+		t.Error("an unknown role can view")
+	}
+	if Can(RoleOwner, Action("launch_rockets")) {
+		t.Error("an unknown action is allowed")
+	}
+}
+
+func TestRolesAllowed(t *testing.T) {
+	for _, row := range matrix {
+		var want []string
+		for i, role := range allRoles {
+			// the owner is never a stored member role
+			if row.allowed[i] && role != RoleOwner {
+				want = append(want, role)
+			}
+		}
+		got := RolesAllowed(row.action)
+		if !slices.Equal(got, want) {
+			t.Errorf("RolesAllowed(%s) = %v, want %v", row.action, got, want)
+		}
+	}
+}
+
+func TestCheckAccess(t *testing.T) {
+	archivedAt := time.Now()
+	active := &Project{}
+	archived := &Project{ArchivedAt: &archivedAt}
+
+	for _, row := range matrix {
+		for i, role := range allRoles {
+			err := checkAccess(active, role, row.action)
+			if row.allowed[i] != (err == nil) {
+				t.Errorf("active project, %s %s: got %v", role, row.action, err)
+			}
+			if err != nil && !strings.HasPrefix(err.Error(), "unauthorized") {
+				t.Errorf("active project, %s %s: error %q does not map to 403", role, row.action, err)
+			}
+
+			err = checkAccess(archived, role, row.action)
+			switch {
+			case !row.allowed[i]:
+				if err == nil || !strings.HasPrefix(err.Error(), "unauthorized") {
+					t.Errorf("archived project, %s %s: got %v, want unauthorized", role, row.action, err)
+				}
+			case row.blockedArchived:
+				if err == nil || err.Error() != "project is archived and read-only" {
+					t.Errorf("archived project, %s %s: got %v, want read-only", role, row.action, err)
+				}
+			default:
+				if err != nil {
+					t.Errorf("archived project, %s %s: got %v, want allowed", role, row.action, err)
+				}
+			}
+		}
+	}
+}
+
+func TestCheckAccessWithoutRole(t *testing.T) {
+	err := checkAccess(&Project{}, "", ActionView)
+	if err == nil || !strings.Contains(err.Error(), "don't have access") {
+		t.Fatalf("got %v", err)
+	}
+}
+
+func TestOutranks(t *testing.T) {
+	for i, a := range allRoles {
+		for j, b := range allRoles {
+			if got, want := Outranks(a, b), i > j; got != want {
+				t.Errorf("Outranks(%s, %s) = %v, want %v", a, b, got, want)
+			}
+		}
+	}
+	if Outranks("", RoleViewer) {
+		t.Error("a non-member outranks a viewer")
+	}
+	if !Outranks(RoleViewer, "") {
+		t.Error("a viewer does not outrank a non-member")
+	}
+}
+
+func TestIsValidRole(t *testing.T) {
+	for _, role := range []string{RoleViewer, RoleEditor, RoleMaintainer, RoleAdmin} {
+		if !IsValidRole(role) {
+			t.Errorf("%s is not a valid member role", role)
+		}
+	}
+	for _, role := range []string{RoleOwner, "", "Admin", "superuser"} {
+		if IsValidRole(role) {
+			t.Errorf("%q is a valid member role", role)
+		}
+	}
+}
@@ -4,11 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+// errNotMember rolls back a transfer to someone who is not a member
+var errNotMember = errors.New("not a member")
+
 type Repository struct {
 	db *gorm.DB
 }
@@ -29,15 +33,80 @@ func (r *Repository) FindByID(ctx context.Context, id int64) (*Project, error) {
 	return &project, nil
 }
 
-func (r *Repository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*Project, error) {
-	var projects []*Project
-	err := r.db.WithContext(ctx).Where("owner_id = ?", userID).Order("created_at DESC").Find(&projects).Error
-	if err != nil {
+// Sort orders for project listings
+const (
+	SortCreated  = "created"
+	SortName     = "name"
+	SortActivity = "activity"
+)
+
+// summaries selects the projects userID owns or is a member of, with the
+// user's role, the member count and the time of the latest change to the
+// project or any of its tasks and subtasks outside the trash
+func (r *Repository) summaries(ctx context.Context, userID uuid.UUID) *gorm.DB {
+	return r.db.WithContext(ctx).Table("project").
+		Select(`project.*,
+			CASE WHEN project.owner_id = ? THEN 'owner' ELSE membership.role END AS role,
+			(SELECT COUNT(*) FROM project_member m WHERE m.project_id = project.id) + 1 AS member_count,
+			GREATEST(project.updated_at,
+				(SELECT MAX(t.updated_at) FROM task t WHERE t.project_id = project.id AND t.deleted_at IS NULL),
+				(SELECT MAX(s.updated_at) FROM subtask s JOIN task t ON t.id = s.task_id WHERE t.project_id = project.id
+					AND s.deleted_at IS NULL AND t.deleted_at IS NULL)
+			) AS last_activity_at`, userID).
+		Joins("LEFT JOIN project_member membership ON membership.project_id = project.id AND membership.user_id = ?", userID).
+		Where("project.owner_id = ? OR membership.user_id IS NOT NULL", userID).
+		Where("project.deleted_at IS NULL")
+}
+
+// FindByUserID lists the projects the user owns or is a member of. Archived
+// projects are only listed, exclusively, with the "archived" filter set. The
+// role filter is "owner" or "member"; an empty sort lists the newest first.
+func (r *Repository) FindByUserID(ctx context.Context, userID uuid.UUID, filters map[string]interface{}, sort string) ([]*ProjectSummary, error) {
+	query := r.summaries(ctx, userID)
+
+	if archived, _ := filters["archived"].(bool); archived {
+		query = query.Where("project.archived_at IS NOT NULL")
+	} else {
+		query = query.Where("project.archived_at IS NULL")
+	}
+
+	if role, ok := filters["role"]; ok {
+		if role == RoleOwner {
+			query = query.Where("project.owner_id = ?", userID)
+		} else {
+			query = query.Where("project.owner_id <> ?", userID)
+		}
+	}
+
+	switch sort {
+	case SortName:
+		query = query.Order("LOWER(project.name)").Order("project.id")
+	case SortActivity:
+		query = query.Order("last_activity_at DESC").Order("project.id DESC")
+	default:
+		query = query.Order("project.created_at DESC")
+	}
+
+	var projects []*ProjectSummary
+	if err := query.Scan(&projects).Error; err != nil {
 		return nil, fmt.Errorf("FindByUserID: %v", err)
 	}
 	return projects, nil
 }
 
+// FindSummary returns one project as seen by userID
+func (r *Repository) FindSummary(ctx context.Context, projectID int64, userID uuid.UUID) (*ProjectSummary, error) {
+	var projects []*ProjectSummary
+	err := r.summaries(ctx, userID).Where("project.id = ?", projectID).Scan(&projects).Error
+	if err != nil {
+		return nil, fmt.Errorf("FindSummary: %v", err)
+	}
+	if len(projects) == 0 {
+		return nil, fmt.Errorf("project not found - FindSummary")
+	}
+	return projects[0], nil
+}
+
 func (r *Repository) Create(ctx context.Context, project *Project) error {
 	err := r.db.WithContext(ctx).Create(project).Error
 	if err != nil {
@@ -54,16 +123,34 @@ func (r *Repository) Update(ctx context.Context, project *Project) error {
 	return nil
 }
 
-func (r *Repository) Delete(ctx context.Context, id int64) error {
-	err := r.db.WithContext(ctx).Delete(&Project{}, id).Error
+// Delete moves the project to the trash together with its tasks and
+// subtasks. They all share one deleted_at, which is how a restore tells them
+// apart from tasks and subtasks that had been trashed on their own before.
+func (r *Repository) Delete(ctx context.Context, id int64, userID uuid.UUID) error {
+	now := time.Now()
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`UPDATE subtask SET deleted_at = ? WHERE deleted_at IS NULL
+			AND task_id IN (SELECT id FROM task WHERE project_id = ? AND deleted_at IS NULL)`, now, id).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Exec("UPDATE task SET deleted_at = ? WHERE project_id = ? AND deleted_at IS NULL", now, id).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&Project{}).Where("id = ?", id).Update("deleted_at", now).Error; err != nil {
+			return err
+		}
+		return RecordEvent(tx, id, userID, EventDeleted, nil)
+	})
 	if err != nil {
 		return fmt.Errorf("project - Delete: %v", err)
 	}
 	return nil
 }
 
-func (r *Repository) AddUser(ctx context.Context, projectID int64, userID uuid.UUID) error {
-	projectMember := ProjectMember{UserID: userID, ProjectID: projectID}
+func (r *Repository) AddUser(ctx context.Context, projectID int64, userID uuid.UUID, role string) error {
+	projectMember := ProjectMember{UserID: userID, ProjectID: projectID, Role: role}
 	err := r.db.WithContext(ctx).Create(&projectMember).Error
 	if err != nil {
 		return fmt.Errorf("AddUser: %v", err)
@@ -74,54 +161,150 @@ func (r *Repository) AddUser(ctx context.Context, projectID int64, userID uuid.U
 
 func (r *Repository) MemberByID(ctx context.Context, projectID int64, targetID uuid.UUID) (*ProjectMember, error) {
 	var projectMember ProjectMember
-	err := r.db.WithContext(ctx).Where("project_id = ?", projectID).First(&projectMember).Error
+	err := r.db.WithContext(ctx).Where("project_id = ? AND user_id = ?", projectID, targetID).First(&projectMember).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
 		}
-		return nil, fmt.Errorf("MemberListBy: %v", err)
+		return nil, fmt.Errorf("MemberByID: %v", err)
 	}
 
 	return &projectMember, nil
 }
 
-func (r *Repository) IsOwner(ctx context.Context, projectID int64, userID uuid.UUID) (bool, error) {
-	project, err := r.FindByID(ctx, projectID)
+// ListMembers returns the members of a project with their names and emails,
+// in the order they were added. The owner is not a member row.
+func (r *Repository) ListMembers(ctx context.Context, projectID int64) ([]*MemberResponse, error) {
+	var members []*MemberResponse
+	err := r.db.WithContext(ctx).Table("project_member").
+		Select("project_member.user_id, app_user.name, app_user.email, app_user.handle, project_member.role").
+		Joins("JOIN app_user ON app_user.id = project_member.user_id").
+		Where("project_member.project_id = ?", projectID).
+		Order("project_member.id").
+		Scan(&members).Error
 	if err != nil {
-		return false, fmt.Errorf("project doesnt exist - HasAccess: %v", err)
+		return nil, fmt.Errorf("ListMembers: %v", err)
 	}
+	return members, nil
+}
 
-	return project.OwnerID == userID, nil
+// UpdateMemberRole reports false when the user is not a member
+func (r *Repository) UpdateMemberRole(ctx context.Context, projectID int64, userID uuid.UUID, role string) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&ProjectMember{}).
+		Where("project_id = ? AND user_id = ?", projectID, userID).
+		Update("role", role)
+	if res.Error != nil {
+		return false, fmt.Errorf("UpdateMemberRole: %v", res.Error)
+	}
+	return res.RowsAffected > 0, nil
 }
 
-func (r *Repository) HasAccess(ctx context.Context, projectID int64, userID uuid.UUID) (bool, error) {
-	// var project Project
-	project, err := r.FindByID(ctx, projectID)
+// RemoveMember deletes the membership, unassigns the user's tasks and
+// subtasks in the project and stops them watching its tasks. It reports false
+// when the user was not a member.
+func (r *Repository) RemoveMember(ctx context.Context, projectID int64, userID uuid.UUID) (bool, error) {
+	removed := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("project_id = ? AND user_id = ?", projectID, userID).Delete(&ProjectMember{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return nil
+		}
+		removed = true
+
+		err := tx.Exec(`DELETE FROM task_assignee
+			WHERE user_id = ? AND task_id IN (SELECT id FROM task WHERE project_id = ?)`,
+			userID, projectID).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Exec(`DELETE FROM task_watcher
+			WHERE user_id = ? AND task_id IN (SELECT id FROM task WHERE project_id = ?)`,
+			userID, projectID).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Exec(`UPDATE subtask SET assigned_to = NULL, updated_at = ?
+			WHERE assigned_to = ? AND task_id IN (SELECT id FROM task WHERE project_id = ?)`,
+			time.Now(), userID, projectID).Error
+	})
 	if err != nil {
-		return false, fmt.Errorf("project doesnt exist - HasAccess: %v", err)
+		return false, fmt.Errorf("RemoveMember: %v", err)
 	}
+	return removed, nil
+}
 
-	if project.OwnerID == userID {
-		return true, nil // User is the owner
-	}
+// TransferOwnership makes newOwnerID, who must be a member, the owner and
+// keeps the previous owner on as a member with previousOwnerRole. It reports
+// false when the ownership or membership changed in the meantime.
+func (r *Repository) TransferOwnership(ctx context.Context, projectID int64, ownerID uuid.UUID, newOwnerID uuid.UUID, previousOwnerRole string) (bool, error) {
+	transferred := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&Project{}).Where("id = ? AND owner_id = ?", projectID, ownerID).
+			Updates(map[string]interface{}{"owner_id": newOwnerID, "updated_at": time.Now()})
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
 
-	// err := r.db.Where("id = ? AND owner_id = ?", projectID, userID).First(&project).Error
-	// if err == nil {
-	// 	return true, nil // User is the owner
-	// }
-	// if !errors.Is(err, gorm.ErrRecordNotFound) {
-	// 	return false, fmt.Errorf("owner check - HasAccess: %v", err)
-	// }
+		// owners are not listed as members of their own project
+		res = tx.Where("project_id = ? AND user_id = ?", projectID, newOwnerID).Delete(&ProjectMember{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return errNotMember
+		}
 
-	// fmt.Println(project)
-	// Check if user is a member
-	var member ProjectMember
-	err = r.db.WithContext(ctx).Where("project_id = ? AND user_id = ?", projectID, userID).First(&member).Error
-	if err == nil {
-		return true, nil // User is a member
+		previous := &ProjectMember{ProjectID: projectID, UserID: ownerID, Role: previousOwnerRole}
+		if err := tx.Create(previous).Error; err != nil {
+			return err
+		}
+
+		transferred = true
+		return RecordEvent(tx, projectID, ownerID, EventOwnershipTransferred, map[string]interface{}{
+			"previous_owner_id":   ownerID,
+			"new_owner_id":        newOwnerID,
+			"previous_owner_role": previousOwnerRole,
+		})
+	})
+	if errors.Is(err, errNotMember) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("TransferOwnership: %v", err)
 	}
-	if errors.Is(err, gorm.ErrRecordNotFound) {
-		return false, nil // User has no access
+	return transferred, nil
+}
+
+// SetArchived archives or restores the project and records it in the
+// history. It reports false when the project already was in that state.
+func (r *Repository) SetArchived(ctx context.Context, projectID int64, userID uuid.UUID, archived bool) (bool, error) {
+	changed := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		query := tx.Model(&Project{}).Where("id = ?", projectID)
+
+		var res *gorm.DB
+		eventType := EventArchived
+		if archived {
+			res = query.Where("archived_at IS NULL").Updates(map[string]interface{}{"archived_at": now, "updated_at": now})
+		} else {
+			eventType = EventUnarchived
+			res = query.Where("archived_at IS NOT NULL").Updates(map[string]interface{}{"archived_at": nil, "updated_at": now})
+		}
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+
+		changed = true
+		return RecordEvent(tx, projectID, userID, eventType, nil)
+	})
+	if err != nil {
+		return false, fmt.Errorf("SetArchived: %v", err)
 	}
-	return false, fmt.Errorf("HasAccess: %v", err)
+	return changed, nil
 }
@@ -5,9 +5,10 @@ import (
 )
 
 func RegisterRoutes(group *gin.RouterGroup, controller *Controller,
-	 authMw gin.HandlerFunc, rateLimitMw gin.HandlerFunc) {
+	 authMw gin.HandlerFunc, scopeMw gin.HandlerFunc, rateLimitMw gin.HandlerFunc) {
 	projects := group.Group("/projects")
 	projects.Use(authMw) 
+	projects.Use(scopeMw)
 	projects.Use(rateLimitMw)
 	{
 		projects.GET("", controller.List)
@@ -16,5 +17,13 @@ func RegisterRoutes(group *gin.RouterGroup, controller *Controller,
 		projects.PUT("/:id", controller.Update)
 		projects.DELETE("/:id", controller.Delete)
 		projects.POST("/:id/members", controller.AddUser)
+		projects.GET("/:id/members", controller.ListMembers)
+		projects.PUT("/:id/members/:userId", controller.UpdateMemberRole)
+		projects.DELETE("/:id/members/:userId", controller.RemoveMember)
+		projects.POST("/:id/leave", controller.Leave)
+		projects.POST("/:id/transfer", controller.Transfer)
+		projects.GET("/:id/history", controller.History)
+		projects.POST("/:id/archive", controller.Archive)
+		projects.POST("/:id/unarchive", controller.Unarchive)
 	}
 }
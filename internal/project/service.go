@@ -5,32 +5,62 @@ import (
 	"fmt"
 	"time"
 
+	"task-management/internal/config"
+	"task-management/internal/user"
+
 	"github.com/google/uuid"
 )
 
 type Service interface {
-	List(ctx context.Context, userID uuid.UUID) ([]*ProjectResponse, error)
+	// List returns the projects the user owns or is a member of
+	List(ctx context.Context, userID uuid.UUID, filters map[string]interface{}, sort string) ([]*ProjectResponse, error)
 	Create(ctx context.Context, userID uuid.UUID, dto *CreateProjectRequest) (*ProjectResponse, error)
 	GetByID(ctx context.Context, projectID int64, userID uuid.UUID) (*ProjectResponse, error)
 	Update(ctx context.Context, projectID int64, userID uuid.UUID, dto *UpdateProjectRequest) (*ProjectResponse, error)
+	// Delete moves the project to the trash, see the trash package
 	Delete(ctx context.Context, projectID int64, userID uuid.UUID) error
-	AddUser(ctx context.Context, projectID int64, userID uuid.UUID, targetID uuid.UUID) error
+	AddUser(ctx context.Context, projectID int64, userID uuid.UUID, dto *AddUserRequest) error
+	// ListMembers is open to everyone with access and lists the owner first
+	ListMembers(ctx context.Context, projectID int64, userID uuid.UUID) ([]*MemberResponse, error)
+	UpdateMemberRole(ctx context.Context, projectID int64, userID uuid.UUID, targetID uuid.UUID, dto *UpdateMemberRoleRequest) error
+	RemoveMember(ctx context.Context, projectID int64, userID uuid.UUID, targetID uuid.UUID) error
+	Leave(ctx context.Context, projectID int64, userID uuid.UUID) error
+	// Transfer hands the project to an existing member
+	Transfer(ctx context.Context, projectID int64, userID uuid.UUID, dto *TransferProjectRequest) (*ProjectResponse, error)
+	History(ctx context.Context, projectID int64, userID uuid.UUID, limit int) ([]*ProjectEventResponse, error)
+	// Archive makes the project read-only and hides it from the default list
+	Archive(ctx context.Context, projectID int64, userID uuid.UUID) (*ProjectResponse, error)
+	Unarchive(ctx context.Context, projectID int64, userID uuid.UUID) (*ProjectResponse, error)
+}
+
+const (
+	defaultHistoryLimit = 50
+	maxHistoryLimit     = 200
+)
+
+// TemplateInstantiator creates a project from a saved template. It lives in
+// the template package, which depends on tasks and subtasks.
+type TemplateInstantiator interface {
+	Instantiate(ctx context.Context, userID uuid.UUID, templateID int64, project *Project, startDate *time.Time) error
 }
 
 type service struct {
-	repo *Repository
+	config    *config.Config
+	repo      *Repository
+	userRepo  *user.Repository
+	templates TemplateInstantiator
 }
 
-func NewService(repo *Repository) Service {
-	return &service{repo: repo}
+func NewService(config *config.Config, repo *Repository, userRepo *user.Repository, templates TemplateInstantiator) Service {
+	return &service{config: config, repo: repo, userRepo: userRepo, templates: templates}
 }
 
-func (s *service) List(ctx context.Context, userID uuid.UUID) ([]*ProjectResponse, error) {
-	projects, err := s.repo.FindByUserID(ctx, userID)
+func (s *service) List(ctx context.Context, userID uuid.UUID, filters map[string]interface{}, sort string) ([]*ProjectResponse, error) {
+	projects, err := s.repo.FindByUserID(ctx, userID, filters, sort)
 	if err != nil {
 		return nil, err
 	}
-	return ToProjectResponseList(projects), nil
+	return ToProjectSummaryResponseList(projects), nil
 }
 
 func (s *service) Create(ctx context.Context, userID uuid.UUID, dto *CreateProjectRequest) (*ProjectResponse, error) {
@@ -43,37 +73,36 @@ func (s *service) Create(ctx context.Context, userID uuid.UUID, dto *CreateProje
 		UpdatedAt:   now,
 	}
 
-	if err := s.repo.Create(ctx, project); err != nil {
+	if dto.TemplateID != nil {
+		if err := s.templates.Instantiate(ctx, userID, *dto.TemplateID, project, dto.StartDate); err != nil {
+			return nil, err
+		}
+	} else if err := s.repo.Create(ctx, project); err != nil {
 		return nil, err
 	}
 
-	return ToProjectResponse(project), nil
+	return ToProjectSummaryResponse(&ProjectSummary{Project: *project, Role: RoleOwner, MemberCount: 1, LastActivityAt: now}), nil
 }
 
 func (s *service) GetByID(ctx context.Context, projectID int64, userID uuid.UUID) (*ProjectResponse, error) {
-	project, err := s.repo.FindByID(ctx, projectID)
-	if err != nil {
+	if _, _, err := s.repo.Authorize(ctx, projectID, userID, ActionView); err != nil {
 		return nil, err
 	}
 
-	//only owner can access
-	if project.OwnerID != userID {
-		return nil, fmt.Errorf("unauthorized: you don't own this project")
+	summary, err := s.repo.FindSummary(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
 	}
 
-	return ToProjectResponse(project), nil
+	return ToProjectSummaryResponse(summary), nil
 }
 
 func (s *service) Update(ctx context.Context, projectID int64, userID uuid.UUID, dto *UpdateProjectRequest) (*ProjectResponse, error) {
-	project, err := s.repo.FindByID(ctx, projectID)
+	project, role, err := s.repo.Authorize(ctx, projectID, userID, ActionEditProject)
 	if err != nil {
 		return nil, err
 	}
 
-	if project.OwnerID != userID {
-		return nil, fmt.Errorf("unauthorized: you don't own this project")
-	}
-
 	if dto.Name != "" {
 		project.Name = dto.Name
 	}
@@ -86,30 +115,42 @@ func (s *service) Update(ctx context.Context, projectID int64, userID uuid.UUID,
 		return nil, err
 	}
 
-	return ToProjectResponse(project), nil
+	res := ToProjectResponse(project)
+	res.Role = role
+	return res, nil
 }
 
 func (s *service) Delete(ctx context.Context, projectID int64, userID uuid.UUID) error {
-	project, err := s.repo.FindByID(ctx, projectID)
-	if err != nil {
+	if _, _, err := s.repo.Authorize(ctx, projectID, userID, ActionDeleteProject); err != nil {
 		return err
 	}
 
-	if project.OwnerID != userID {
-		return fmt.Errorf("unauthorized: you don't own this project - Delete: ")
+	return s.repo.Delete(ctx, projectID, userID)
+}
+
+func (s *service) AddUser(ctx context.Context, projectID int64, userID uuid.UUID, dto *AddUserRequest) error {
+	project, role, err := s.repo.Authorize(ctx, projectID, userID, ActionManageMembers)
+	if err != nil {
+		return fmt.Errorf("AddUser: %v", err)
 	}
 
-	return s.repo.Delete(ctx, projectID)
-}
+	targetID := dto.UserID
+	newRole := dto.Role
+	if newRole == "" {
+		newRole = RoleEditor
+	}
+	// nobody can hand out a role at or above their own, except the owner
+	if role != RoleOwner && !Outranks(role, newRole) {
+		return fmt.Errorf("unauthorized: %s role cannot grant %s - AddUser", role, newRole)
+	}
 
-func (s *service) AddUser(ctx context.Context, projectID int64, userID uuid.UUID, targetID uuid.UUID) error {
-	project, err := s.repo.FindByID(ctx, projectID)
+	target, err := s.userRepo.FindByID(ctx, targetID)
 	if err != nil {
 		return fmt.Errorf("AddUser: %v", err)
 	}
 
-	if project.OwnerID != userID {
-		return fmt.Errorf("unauthorized: you don't own this project - AddUser: ")
+	if s.config.Auth.RestrictUnverified && target.EmailVerifiedAt == nil {
+		return fmt.Errorf("user email not verified - AddUser")
 	}
 
 	projectMember, err := s.repo.MemberByID(ctx, projectID, targetID)
@@ -117,16 +158,198 @@ func (s *service) AddUser(ctx context.Context, projectID int64, userID uuid.UUID
 		return fmt.Errorf("AddUser: %v", err)
 	}
 
-	if projectMember != nil {
-		if userID == projectMember.UserID {
-			return fmt.Errorf("member already added to project - AddUser: %v", err)
-		}
+	if projectMember != nil || targetID == project.OwnerID {
+		return fmt.Errorf("member already added to project - AddUser")
 	}
 
-	err = s.repo.AddUser(ctx, projectID, targetID)
+	err = s.repo.AddUser(ctx, projectID, targetID, newRole)
 	if err != nil {
 		return fmt.Errorf("AddUser: %v", err)
 	}
 
 	return nil
 }
+
+func (s *service) ListMembers(ctx context.Context, projectID int64, userID uuid.UUID) ([]*MemberResponse, error) {
+	project, _, err := s.repo.Authorize(ctx, projectID, userID, ActionView)
+	if err != nil {
+		return nil, fmt.Errorf("ListMembers: %v", err)
+	}
+
+	owner, err := s.userRepo.FindByID(ctx, project.OwnerID)
+	if err != nil {
+		return nil, fmt.Errorf("ListMembers: %v", err)
+	}
+
+	members, err := s.repo.ListMembers(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("ListMembers: %v", err)
+	}
+
+	res := []*MemberResponse{{UserID: owner.ID, Name: owner.Name, Email: owner.Email, Handle: owner.Handle, Role: RoleOwner}}
+	return append(res, members...), nil
+}
+
+// manageableMember checks that the acting user may change targetID's
+// membership and returns the target's current role
+func (s *service) manageableMember(ctx context.Context, projectID int64, userID uuid.UUID, targetID uuid.UUID) (string, string, error) {
+	project, role, err := s.repo.Authorize(ctx, projectID, userID, ActionManageMembers)
+	if err != nil {
+		return "", "", err
+	}
+	if targetID == project.OwnerID {
+		return "", "", fmt.Errorf("the owner cannot be removed from the project or change role")
+	}
+
+	targetRole, err := s.repo.RoleOf(ctx, project, targetID)
+	if err != nil {
+		return "", "", err
+	}
+	if targetRole == "" {
+		return "", "", fmt.Errorf("member not found")
+	}
+	if !Outranks(role, targetRole) {
+		return "", "", fmt.Errorf("unauthorized: %s role cannot manage a member with %s role", role, targetRole)
+	}
+
+	return role, targetRole, nil
+}
+
+func (s *service) UpdateMemberRole(ctx context.Context, projectID int64, userID uuid.UUID, targetID uuid.UUID, dto *UpdateMemberRoleRequest) error {
+	role, _, err := s.manageableMember(ctx, projectID, userID, targetID)
+	if err != nil {
+		return fmt.Errorf("UpdateMemberRole: %v", err)
+	}
+	if role != RoleOwner && !Outranks(role, dto.Role) {
+		return fmt.Errorf("unauthorized: %s role cannot grant %s - UpdateMemberRole", role, dto.Role)
+	}
+
+	updated, err := s.repo.UpdateMemberRole(ctx, projectID, targetID, dto.Role)
+	if err != nil {
+		return fmt.Errorf("UpdateMemberRole: %v", err)
+	}
+	if !updated {
+		return fmt.Errorf("member not found - UpdateMemberRole")
+	}
+
+	return nil
+}
+
+func (s *service) RemoveMember(ctx context.Context, projectID int64, userID uuid.UUID, targetID uuid.UUID) error {
+	if _, _, err := s.manageableMember(ctx, projectID, userID, targetID); err != nil {
+		return fmt.Errorf("RemoveMember: %v", err)
+	}
+
+	removed, err := s.repo.RemoveMember(ctx, projectID, targetID)
+	if err != nil {
+		return fmt.Errorf("RemoveMember: %v", err)
+	}
+	if !removed {
+		return fmt.Errorf("member not found - RemoveMember")
+	}
+
+	return nil
+}
+
+func (s *service) Leave(ctx context.Context, projectID int64, userID uuid.UUID) error {
+	project, err := s.repo.FindByID(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("Leave: %v", err)
+	}
+
+	if project.OwnerID == userID {
+		return fmt.Errorf("the owner cannot leave the project, delete it instead - Leave")
+	}
+
+	removed, err := s.repo.RemoveMember(ctx, projectID, userID)
+	if err != nil {
+		return fmt.Errorf("Leave: %v", err)
+	}
+	if !removed {
+		return fmt.Errorf("member not found - Leave")
+	}
+
+	return nil
+}
+
+func (s *service) Transfer(ctx context.Context, projectID int64, userID uuid.UUID, dto *TransferProjectRequest) (*ProjectResponse, error) {
+	project, _, err := s.repo.Authorize(ctx, projectID, userID, ActionTransfer)
+	if err != nil {
+		return nil, fmt.Errorf("Transfer: %v", err)
+	}
+	if dto.UserID == project.OwnerID {
+		return nil, fmt.Errorf("you already own this project - Transfer")
+	}
+
+	previousOwnerRole := dto.PreviousOwnerRole
+	if previousOwnerRole == "" {
+		previousOwnerRole = s.config.Project.TransferPreviousOwnerRole
+	}
+
+	transferred, err := s.repo.TransferOwnership(ctx, projectID, userID, dto.UserID, previousOwnerRole)
+	if err != nil {
+		return nil, fmt.Errorf("Transfer: %v", err)
+	}
+	if !transferred {
+		return nil, fmt.Errorf("member not found - Transfer")
+	}
+
+	summary, err := s.repo.FindSummary(ctx, projectID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("Transfer: %v", err)
+	}
+
+	return ToProjectSummaryResponse(summary), nil
+}
+
+func (s *service) History(ctx context.Context, projectID int64, userID uuid.UUID, limit int) ([]*ProjectEventResponse, error) {
+	if _, _, err := s.repo.Authorize(ctx, projectID, userID, ActionView); err != nil {
+		return nil, fmt.Errorf("History: %v", err)
+	}
+
+	if limit <= 0 {
+		limit = defaultHistoryLimit
+	}
+	if limit > maxHistoryLimit {
+		limit = maxHistoryLimit
+	}
+
+	events, err := s.repo.ListEvents(ctx, projectID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("History: %v", err)
+	}
+
+	return ToProjectEventResponseList(events), nil
+}
+
+func (s *service) Archive(ctx context.Context, projectID int64, userID uuid.UUID) (*ProjectResponse, error) {
+	return s.setArchived(ctx, projectID, userID, true)
+}
+
+func (s *service) Unarchive(ctx context.Context, projectID int64, userID uuid.UUID) (*ProjectResponse, error) {
+	return s.setArchived(ctx, projectID, userID, false)
+}
+
+func (s *service) setArchived(ctx context.Context, projectID int64, userID uuid.UUID, archived bool) (*ProjectResponse, error) {
+	if _, _, err := s.repo.Authorize(ctx, projectID, userID, ActionArchive); err != nil {
+		return nil, fmt.Errorf("setArchived: %v", err)
+	}
+
+	changed, err := s.repo.SetArchived(ctx, projectID, userID, archived)
+	if err != nil {
+		return nil, fmt.Errorf("setArchived: %v", err)
+	}
+	if !changed && archived {
+		return nil, fmt.Errorf("project is already archived")
+	}
+	if !changed {
+		return nil, fmt.Errorf("project is not archived")
+	}
+
+	summary, err := s.repo.FindSummary(ctx, projectID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("setArchived: %v", err)
+	}
+
+	return ToProjectSummaryResponse(summary), nil
+}
@@ -0,0 +1,41 @@
+package security
+
+import (
+	"fmt"
+	"time"
+)
+
+type LoginEventResponse struct {
+	ID        int64     `json:"id"`
+	IPAddress string    `json:"ip_address"`
+	UserAgent string    `json:"user_agent"`
+	Outcome   string    `json:"outcome"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func ToLoginEventResponse(event *LoginEvent) *LoginEventResponse {
+	return &LoginEventResponse{
+		ID:        event.ID,
+		IPAddress: event.IPAddress,
+		UserAgent: event.UserAgent,
+		Outcome:   event.Outcome,
+		CreatedAt: event.CreatedAt,
+	}
+}
+
+func ToLoginEventResponseList(events []*LoginEvent) []*LoginEventResponse {
+	responses := make([]*LoginEventResponse, len(events))
+	for i, event := range events {
+		responses[i] = ToLoginEventResponse(event)
+	}
+	return responses
+}
+
+// LockedError is returned while an account is locked out after too many failed logins
+type LockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("account temporarily locked, retry in %d seconds", int(e.RetryAfter.Seconds()))
+}
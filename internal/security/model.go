@@ -0,0 +1,30 @@
+package security
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Login outcomes recorded in login_event
+const (
+	OutcomeSuccess            = "success"
+	OutcomeInvalidCredentials = "invalid_credentials"
+	OutcomeLocked             = "locked"
+	OutcomeTwoFactorRequired  = "two_factor_required"
+	OutcomeTwoFactorFailed    = "two_factor_failed"
+)
+
+type LoginEvent struct {
+	ID        int64      `gorm:"primaryKey;autoIncrement"`
+	UserID    *uuid.UUID `gorm:"type:uuid;index"`
+	Email     string     `gorm:"type:varchar(255);not null"`
+	IPAddress string     `gorm:"column:ip_address;type:varchar(64)"`
+	UserAgent string     `gorm:"type:varchar(512)"`
+	Outcome   string     `gorm:"type:varchar(32);not null"`
+	CreatedAt time.Time  `gorm:"type:timestamp;not null"`
+}
+
+func (LoginEvent) TableName() string {
+	return "login_event"
+}
@@ -0,0 +1,35 @@
+package security
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) CreateEvent(ctx context.Context, event *LoginEvent) error {
+	err := r.db.WithContext(ctx).Create(event).Error
+	if err != nil {
+		return fmt.Errorf("CreateEvent: %v", err)
+	}
+	return nil
+}
+
+func (r *Repository) FindEventsByUserID(ctx context.Context, userID uuid.UUID, limit int) ([]*LoginEvent, error) {
+	var events []*LoginEvent
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).
+		Order("created_at DESC").Limit(limit).Find(&events).Error
+	if err != nil {
+		return nil, fmt.Errorf("FindEventsByUserID: %v", err)
+	}
+	return events, nil
+}
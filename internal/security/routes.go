@@ -0,0 +1,16 @@
+package security
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRoutes(group *gin.RouterGroup, controller *Controller,
+	authMw gin.HandlerFunc, scopeMw gin.HandlerFunc, rateLimitMw gin.HandlerFunc) {
+	security := group.Group("/users/me/security")
+	security.Use(authMw)
+	security.Use(scopeMw)
+	security.Use(rateLimitMw)
+	{
+		security.GET("/events", controller.Events)
+	}
+}
@@ -0,0 +1,144 @@
+package security
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"task-management/internal/config"
+	"task-management/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	defaultEventLimit = 20
+	maxEventLimit     = 100
+)
+
+type Service interface {
+	// CheckLockout returns a *LockedError while the email is locked out
+	CheckLockout(ctx context.Context, email string) error
+	RegisterFailure(ctx context.Context, email string) error
+	ResetFailures(ctx context.Context, email string) error
+	RecordEvent(ctx context.Context, event *LoginEvent)
+	RecentEvents(ctx context.Context, userID uuid.UUID, limit int) ([]*LoginEventResponse, error)
+}
+
+type service struct {
+	config *config.Config
+	repo   *Repository
+	rdb    *redis.Client
+}
+
+func NewService(config *config.Config, repo *Repository, rdb *redis.Client) Service {
+	return &service{config: config, repo: repo, rdb: rdb}
+}
+
+func failureKey(email string) string {
+	return "login:failures:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func lockKey(email string) string {
+	return "login:lock:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func (s *service) CheckLockout(ctx context.Context, email string) error {
+	ttl, err := s.rdb.TTL(ctx, lockKey(email)).Result()
+	if err != nil {
+		return fmt.Errorf("CheckLockout: %v", err)
+	}
+	if ttl > 0 {
+		return &LockedError{RetryAfter: ttl}
+	}
+	return nil
+}
+
+// RegisterFailure counts failures per email across all IPs. Once the limit
+// is hit every further failure doubles the lockout, up to the configured cap.
+func (s *service) RegisterFailure(ctx context.Context, email string) error {
+	key := failureKey(email)
+	window := time.Minute * time.Duration(s.config.Security.FailureWindowMinutes)
+
+	failures, err := s.rdb.Incr(ctx, key).Result()
+	if err != nil {
+		return fmt.Errorf("RegisterFailure: %v", err)
+	}
+	if err := s.rdb.Expire(ctx, key, window).Err(); err != nil {
+		return fmt.Errorf("RegisterFailure: %v", err)
+	}
+
+	lockout := s.lockoutAfter(failures)
+	if lockout == 0 {
+		return nil
+	}
+
+	// keep counting failures for as long as the lock lasts so the next one is longer
+	if lockout > window {
+		if err := s.rdb.Expire(ctx, key, lockout).Err(); err != nil {
+			return fmt.Errorf("RegisterFailure: %v", err)
+		}
+	}
+
+	if err := s.rdb.Set(ctx, lockKey(email), 1, lockout).Err(); err != nil {
+		return fmt.Errorf("RegisterFailure: %v", err)
+	}
+
+	return nil
+}
+
+// lockoutAfter is how long the given number of failures locks an email out:
+// nothing below the limit, the base lockout at it, doubling after that
+func (s *service) lockoutAfter(failures int64) time.Duration {
+	over := failures - int64(s.config.Security.MaxLoginAttempts)
+	if over < 0 {
+		return 0
+	}
+
+	lockout := time.Second * time.Duration(s.config.Security.LockoutBaseSeconds)
+	maxLockout := time.Minute * time.Duration(s.config.Security.LockoutMaxMinutes)
+	for i := int64(0); i < over && lockout < maxLockout; i++ {
+		lockout *= 2
+	}
+	if lockout > maxLockout {
+		lockout = maxLockout
+	}
+	return lockout
+}
+
+func (s *service) ResetFailures(ctx context.Context, email string) error {
+	if err := s.rdb.Del(ctx, failureKey(email), lockKey(email)).Err(); err != nil {
+		return fmt.Errorf("ResetFailures: %v", err)
+	}
+	return nil
+}
+
+// RecordEvent never fails the login itself; a lost audit row is only logged
+func (s *service) RecordEvent(ctx context.Context, event *LoginEvent) {
+	event.UserAgent = utils.Truncate(event.UserAgent, 512)
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+
+	if err := s.repo.CreateEvent(ctx, event); err != nil {
+		log.Printf("failed to record login event - RecordEvent: %v", err)
+	}
+}
+
+func (s *service) RecentEvents(ctx context.Context, userID uuid.UUID, limit int) ([]*LoginEventResponse, error) {
+	if limit <= 0 {
+		limit = defaultEventLimit
+	}
+	if limit > maxEventLimit {
+		limit = maxEventLimit
+	}
+
+	events, err := s.repo.FindEventsByUserID(ctx, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return ToLoginEventResponseList(events), nil
+}
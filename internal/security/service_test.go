@@ -0,0 +1,113 @@
+package security
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"task-management/internal/config"
+	"task-management/internal/testutil"
+)
+
+func newTestService() *service {
+	cfg := &config.Config{Security: config.SecurityConfig{
+		MaxLoginAttempts:     3,
+		FailureWindowMinutes: 5,
+		LockoutBaseSeconds:   60,
+		LockoutMaxMinutes:    10,
+	}}
+	return NewService(cfg, nil, testutil.NewRedis()).(*service)
+}
+
+// lockouts is the lock each failure count leads to with newTestService's settings
+var lockouts = []struct {
+	failures int64
+	want     time.Duration
+}{
+	{1, 0},
+	{2, 0},
+	{3, time.Minute},
+	{4, 2 * time.Minute},
+	{5, 4 * time.Minute},
+	{6, 8 * time.Minute},
+	{7, 10 * time.Minute},
+	{8, 10 * time.Minute},
+	{50, 10 * time.Minute},
+}
+
+func TestLockoutAfter(t *testing.T) {
+	s := newTestService()
+	for _, tt := range lockouts {
+		if got := s.lockoutAfter(tt.failures); got != tt.want {
+			t.Errorf("lockoutAfter(%d) = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+}
+
+func TestRegisterFailureLocksOut(t *testing.T) {
+	ctx := context.Background()
+	s := newTestService()
+
+	for failures := int64(1); failures <= 8; failures++ {
+		if err := s.RegisterFailure(ctx, " Alice@Example.com"); err != nil {
+			t.Fatal(err)
+		}
+
+		var want time.Duration
+		for _, tt := range lockouts {
+			if tt.failures == failures {
+				want = tt.want
+			}
+		}
+
+		err := s.CheckLockout(ctx, "alice@example.com")
+		var locked *LockedError
+		if want == 0 {
+			if err != nil {
+				t.Fatalf("after %d failures: %v, want no lock", failures, err)
+			}
+			continue
+		}
+		if !errors.As(err, &locked) {
+			t.Fatalf("after %d failures: %v, want a *LockedError", failures, err)
+		}
+		if locked.RetryAfter != want {
+			t.Errorf("after %d failures: locked for %v, want %v", failures, locked.RetryAfter, want)
+		}
+
+		// the count has to outlive a lock longer than the failure window
+		ttl, err := s.rdb.TTL(ctx, failureKey("alice@example.com")).Result()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if wantTTL := max(want, 5*time.Minute); ttl != wantTTL {
+			t.Errorf("after %d failures: failures kept for %v, want %v", failures, ttl, wantTTL)
+		}
+	}
+}
+
+func TestResetFailuresLiftsTheLock(t *testing.T) {
+	ctx := context.Background()
+	s := newTestService()
+
+	for i := 0; i < 4; i++ {
+		if err := s.RegisterFailure(ctx, "bob@example.com"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.ResetFailures(ctx, "bob@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.CheckLockout(ctx, "bob@example.com"); err != nil {
+		t.Fatalf("still locked: %v", err)
+	}
+
+	// counting starts over
+	if err := s.RegisterFailure(ctx, "bob@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.CheckLockout(ctx, "bob@example.com"); err != nil {
+		t.Fatalf("locked after one failure: %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+package session
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type Controller struct {
+	service Service
+}
+
+func NewController(service Service) *Controller {
+	return &Controller{service: service}
+}
+
+func (controller *Controller) List(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.IndentedJSON(401, gin.H{"error": "unauthorized"})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+
+	currentID, _ := c.Get("sessionID")
+	currentUUID, _ := currentID.(uuid.UUID)
+
+	sessions, err := controller.service.List(c.Request.Context(), userUUID, currentUUID)
+	if err != nil {
+		c.IndentedJSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.IndentedJSON(200, sessions)
+}
+
+func (controller *Controller) Revoke(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.IndentedJSON(401, gin.H{"error": "unauthorized"})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.IndentedJSON(400, gin.H{"error": "invalid session ID"})
+		return
+	}
+
+	err = controller.service.Revoke(c.Request.Context(), userUUID, sessionID)
+	if err != nil {
+		if err.Error() == "session not found" {
+			c.IndentedJSON(404, gin.H{"error": err.Error()})
+			return
+		}
+		c.IndentedJSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.IndentedJSON(200, gin.H{"message": "session revoked"})
+}
@@ -0,0 +1,44 @@
+package session
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ClientInfo describes the device a session is created for
+type ClientInfo struct {
+	DeviceName string
+	IP         string
+	UserAgent  string
+}
+
+type SessionResponse struct {
+	ID         uuid.UUID `json:"id"`
+	DeviceName string    `json:"device_name"`
+	IPAddress  string    `json:"ip_address"`
+	UserAgent  string    `json:"user_agent"`
+	Current    bool      `json:"current"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+}
+
+func ToSessionResponse(session *Session, currentID uuid.UUID) *SessionResponse {
+	return &SessionResponse{
+		ID:         session.ID,
+		DeviceName: session.DeviceName,
+		IPAddress:  session.IPAddress,
+		UserAgent:  session.UserAgent,
+		Current:    session.ID == currentID,
+		CreatedAt:  session.CreatedAt,
+		LastSeenAt: session.LastSeenAt,
+	}
+}
+
+func ToSessionResponseList(sessions []*Session, currentID uuid.UUID) []*SessionResponse {
+	responses := make([]*SessionResponse, len(sessions))
+	for i, session := range sessions {
+		responses[i] = ToSessionResponse(session, currentID)
+	}
+	return responses
+}
@@ -0,0 +1,24 @@
+package session
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session is one logged-in device. Its ID is embedded in every access token
+// and doubles as the family of the refresh tokens issued to that device.
+type Session struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	UserID     uuid.UUID  `gorm:"type:uuid;not null;index"`
+	DeviceName string     `gorm:"type:varchar(255);not null"`
+	IPAddress  string     `gorm:"column:ip_address;type:varchar(64)"`
+	UserAgent  string     `gorm:"type:varchar(512)"`
+	CreatedAt  time.Time  `gorm:"type:timestamp;not null"`
+	LastSeenAt time.Time  `gorm:"type:timestamp;not null"`
+	RevokedAt  *time.Time `gorm:"type:timestamp"`
+}
+
+func (Session) TableName() string {
+	return "session"
+}
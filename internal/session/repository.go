@@ -0,0 +1,95 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) Create(ctx context.Context, session *Session) error {
+	err := r.db.WithContext(ctx).Create(session).Error
+	if err != nil {
+		return fmt.Errorf("session - Create: %v", err)
+	}
+	return nil
+}
+
+func (r *Repository) FindByID(ctx context.Context, id uuid.UUID) (*Session, error) {
+	var session Session
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&session).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("session not found")
+		}
+		return nil, fmt.Errorf("FindByID: %v", err)
+	}
+	return &session, nil
+}
+
+// FindActiveByUserID lists sessions that are neither revoked nor idle since before activeSince
+func (r *Repository) FindActiveByUserID(ctx context.Context, userID uuid.UUID, activeSince time.Time) ([]*Session, error) {
+	var sessions []*Session
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND last_seen_at > ?", userID, activeSince).
+		Order("last_seen_at DESC").Find(&sessions).Error
+	if err != nil {
+		return nil, fmt.Errorf("FindActiveByUserID: %v", err)
+	}
+	return sessions, nil
+}
+
+func (r *Repository) Touch(ctx context.Context, id uuid.UUID, ip string, at time.Time) error {
+	err := r.db.WithContext(ctx).Model(&Session{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"last_seen_at": at, "ip_address": ip}).Error
+	if err != nil {
+		return fmt.Errorf("Touch: %v", err)
+	}
+	return nil
+}
+
+func (r *Repository) Revoke(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	res := r.db.WithContext(ctx).Model(&Session{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", time.Now())
+	if res.Error != nil {
+		return fmt.Errorf("session - Revoke: %v", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("session not found")
+	}
+	return nil
+}
+
+// RevokeAllExcept revokes every active session of the user but keepID and returns the revoked IDs
+func (r *Repository) RevokeAllExcept(ctx context.Context, userID uuid.UUID, keepID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).Model(&Session{}).
+		Where("user_id = ? AND id <> ? AND revoked_at IS NULL", userID, keepID).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return nil, fmt.Errorf("RevokeAllExcept: %v", err)
+	}
+	if len(ids) == 0 {
+		return ids, nil
+	}
+
+	err = r.db.WithContext(ctx).Model(&Session{}).
+		Where("id IN ?", ids).
+		Update("revoked_at", time.Now()).Error
+	if err != nil {
+		return nil, fmt.Errorf("RevokeAllExcept: %v", err)
+	}
+	return ids, nil
+}
@@ -0,0 +1,17 @@
+package session
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRoutes(group *gin.RouterGroup, controller *Controller,
+	authMw gin.HandlerFunc, scopeMw gin.HandlerFunc, rateLimitMw gin.HandlerFunc) {
+	sessions := group.Group("/users/me/sessions")
+	sessions.Use(authMw)
+	sessions.Use(scopeMw)
+	sessions.Use(rateLimitMw)
+	{
+		sessions.GET("", controller.List)
+		sessions.DELETE("/:id", controller.Revoke)
+	}
+}
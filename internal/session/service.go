@@ -0,0 +1,189 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"task-management/internal/config"
+	"task-management/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// last_seen_at is written at most once per interval for each session
+const touchInterval = time.Minute
+
+type Service interface {
+	Create(ctx context.Context, userID uuid.UUID, client *ClientInfo) (*Session, error)
+	List(ctx context.Context, userID uuid.UUID, currentID uuid.UUID) ([]*SessionResponse, error)
+	Revoke(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID) error
+	RevokeAllExcept(ctx context.Context, userID uuid.UUID, keepID uuid.UUID) error
+	// IsRevoked is the cheap per-request check used by the auth middleware
+	IsRevoked(ctx context.Context, sessionID uuid.UUID) (bool, error)
+	// Validate checks the database copy and is used before refreshing tokens
+	Validate(ctx context.Context, sessionID uuid.UUID) error
+	Touch(ctx context.Context, sessionID uuid.UUID, ip string) error
+}
+
+type service struct {
+	config *config.Config
+	repo   *Repository
+	rdb    *redis.Client
+}
+
+func NewService(config *config.Config, repo *Repository, rdb *redis.Client) Service {
+	return &service{config: config, repo: repo, rdb: rdb}
+}
+
+func revokedKey(id uuid.UUID) string {
+	return "session:revoked:" + id.String()
+}
+
+func seenKey(id uuid.UUID) string {
+	return "session:seen:" + id.String()
+}
+
+func (s *service) Create(ctx context.Context, userID uuid.UUID, client *ClientInfo) (*Session, error) {
+	deviceName := utils.Truncate(strings.TrimSpace(client.DeviceName), 255)
+	if deviceName == "" {
+		deviceName = deviceNameFromUserAgent(client.UserAgent)
+	}
+
+	userAgent := utils.Truncate(client.UserAgent, 512)
+
+	now := time.Now()
+	session := &Session{
+		ID:         uuid.New(),
+		UserID:     userID,
+		DeviceName: deviceName,
+		IPAddress:  client.IP,
+		UserAgent:  userAgent,
+		CreatedAt:  now,
+		LastSeenAt: now,
+	}
+
+	if err := s.repo.Create(ctx, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+func (s *service) List(ctx context.Context, userID uuid.UUID, currentID uuid.UUID) ([]*SessionResponse, error) {
+	// a session idle for longer than a refresh token lives can never come back
+	activeSince := time.Now().Add(-time.Hour * time.Duration(s.config.JWT.RefreshExpireHours))
+
+	sessions, err := s.repo.FindActiveByUserID(ctx, userID, activeSince)
+	if err != nil {
+		return nil, err
+	}
+	return ToSessionResponseList(sessions, currentID), nil
+}
+
+func (s *service) Revoke(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID) error {
+	if err := s.repo.Revoke(ctx, sessionID, userID); err != nil {
+		return err
+	}
+	return s.markRevoked(ctx, sessionID)
+}
+
+func (s *service) RevokeAllExcept(ctx context.Context, userID uuid.UUID, keepID uuid.UUID) error {
+	ids, err := s.repo.RevokeAllExcept(ctx, userID, keepID)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if err := s.markRevoked(ctx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// markRevoked only has to outlive the access tokens already handed out for
+// the session; refreshing is blocked by the database row
+func (s *service) markRevoked(ctx context.Context, sessionID uuid.UUID) error {
+	ttl := time.Minute * time.Duration(s.config.JWT.AccessExpireMinutes)
+	if err := s.rdb.Set(ctx, revokedKey(sessionID), 1, ttl).Err(); err != nil {
+		return fmt.Errorf("markRevoked: %v", err)
+	}
+	return nil
+}
+
+func (s *service) IsRevoked(ctx context.Context, sessionID uuid.UUID) (bool, error) {
+	count, err := s.rdb.Exists(ctx, revokedKey(sessionID)).Result()
+	if err != nil {
+		return false, fmt.Errorf("IsRevoked: %v", err)
+	}
+	return count > 0, nil
+}
+
+func (s *service) Validate(ctx context.Context, sessionID uuid.UUID) error {
+	session, err := s.repo.FindByID(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("Validate: %v", err)
+	}
+	if session.RevokedAt != nil {
+		return fmt.Errorf("session revoked")
+	}
+	return nil
+}
+
+func (s *service) Touch(ctx context.Context, sessionID uuid.UUID, ip string) error {
+	first, err := s.rdb.SetNX(ctx, seenKey(sessionID), 1, touchInterval).Result()
+	if err != nil {
+		return fmt.Errorf("Touch: %v", err)
+	}
+	if !first {
+		return nil
+	}
+	return s.repo.Touch(ctx, sessionID, ip, time.Now())
+}
+
+// deviceNameFromUserAgent gives sessions a readable default like "Firefox on Linux"
+func deviceNameFromUserAgent(userAgent string) string {
+	platforms := []struct{ match, name string }{
+		{"iPhone", "iPhone"},
+		{"iPad", "iPad"},
+		{"Android", "Android"},
+		{"Windows", "Windows"},
+		{"Mac OS X", "macOS"},
+		{"Linux", "Linux"},
+	}
+	clients := []struct{ match, name string }{
+		{"Edg/", "Edge"},
+		{"Firefox/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+		{"curl/", "curl"},
+		{"PostmanRuntime/", "Postman"},
+	}
+
+	client := ""
+	for _, c := range clients {
+		if strings.Contains(userAgent, c.match) {
+			client = c.name
+			break
+		}
+	}
+	platform := ""
+	for _, p := range platforms {
+		if strings.Contains(userAgent, p.match) {
+			platform = p.name
+			break
+		}
+	}
+
+	switch {
+	case client != "" && platform != "":
+		return client + " on " + platform
+	case client != "":
+		return client
+	case platform != "":
+		return platform
+	default:
+		return "Unknown device"
+	}
+}
@@ -91,10 +91,18 @@ func (ctrl *Controller) Create(c *gin.Context) {
 			c.IndentedJSON(404, gin.H{"error": err.Error()})
 			return
 		}
+		if strings.Contains(err.Error(), "not a member") {
+			c.IndentedJSON(422, gin.H{"error": err.Error()})
+			return
+		}
 		if strings.Contains(err.Error(), "unauthorized") {
 			c.IndentedJSON(403, gin.H{"error": err.Error()})
 			return
 		}
+		if strings.Contains(err.Error(), "archived") {
+			c.IndentedJSON(409, gin.H{"error": err.Error()})
+			return
+		}
 		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -163,10 +171,18 @@ func (ctrl *Controller) Update(c *gin.Context) {
 			c.IndentedJSON(http.StatusNotFound, gin.H{"error": err.Error()})
 			return
 		}
+		if strings.Contains(err.Error(), "not a member") {
+			c.IndentedJSON(422, gin.H{"error": err.Error()})
+			return
+		}
 		if strings.Contains(err.Error(), "unauthorized") {
 			c.IndentedJSON(http.StatusForbidden, gin.H{"error": err.Error()})
 			return
 		}
+		if strings.Contains(err.Error(), "archived") {
+			c.IndentedJSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -200,6 +216,10 @@ func (ctrl *Controller) ToggleComplete(c *gin.Context) {
 			c.IndentedJSON(http.StatusForbidden, gin.H{"error": err.Error()})
 			return
 		}
+		if strings.Contains(err.Error(), "archived") {
+			c.IndentedJSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -235,6 +255,10 @@ func (ctrl *Controller) Delete(c *gin.Context) {
 			c.IndentedJSON(http.StatusForbidden, gin.H{"error": err.Error()})
 			return
 		}
+		if strings.Contains(err.Error(), "archived") {
+			c.IndentedJSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
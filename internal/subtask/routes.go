@@ -3,17 +3,25 @@ package subtask
 import "github.com/gin-gonic/gin"
 
 func RegisterRoutes(group *gin.RouterGroup, ctrl *Controller,
-	 authMiddleware gin.HandlerFunc, rateLimitMw gin.HandlerFunc) {
+	 authMiddleware gin.HandlerFunc, scopeMw gin.HandlerFunc, rateLimitMw gin.HandlerFunc) {
 	taskRoutes := group.Group("tasks/:id/subtasks")
 	taskRoutes.Use(authMiddleware)
+	taskRoutes.Use(scopeMw)
 	taskRoutes.Use(rateLimitMw)
 
 	taskRoutes.GET("", ctrl.List)
 	taskRoutes.POST("", ctrl.Create)
 
-	// taskRoutes.GET("/:id", ctrl.GetByID)
-	taskRoutes.PUT("/:id", ctrl.Update)
-	taskRoutes.DELETE("/:id", ctrl.Delete)
-	taskRoutes.PATCH("/:id/complete", ctrl.ToggleComplete)
+	// single subtasks are addressed on their own, c.Param("id") under
+	// tasks/:id/subtasks would be the task's id
+	subtaskRoutes := group.Group("subtasks")
+	subtaskRoutes.Use(authMiddleware)
+	subtaskRoutes.Use(scopeMw)
+	subtaskRoutes.Use(rateLimitMw)
+
+	subtaskRoutes.GET("/:id", ctrl.GetByID)
+	subtaskRoutes.PUT("/:id", ctrl.Update)
+	subtaskRoutes.DELETE("/:id", ctrl.Delete)
+	subtaskRoutes.PATCH("/:id/complete", ctrl.ToggleComplete)
 
 }
@@ -3,9 +3,13 @@ package subtask
 import (
 	"context"
 	"fmt"
+	"log"
 	"strings"
 	"time"
 
+	"task-management/internal/mention"
+	"task-management/internal/notification"
+	"task-management/internal/project"
 	"task-management/internal/task"
 
 	"github.com/google/uuid"
@@ -21,32 +25,120 @@ type Service interface {
 }
 
 type service struct {
-	repo        *Repository
-	taskService task.Service
-	// projectService project.Service
+	repo          *Repository
+	taskService   task.Service
+	taskRepo      *task.Repository
+	projectRepo   *project.Repository
+	mentions      mention.Service
+	notifications notification.Service
 }
 
-func NewService(repo *Repository, taskService task.Service) Service {
+func NewService(repo *Repository, taskService task.Service, taskRepo *task.Repository, projectRepo *project.Repository,
+	mentions mention.Service, notifications notification.Service) Service {
 	return &service{
-		repo:        repo,
-		taskService: taskService,
+		repo:          repo,
+		taskService:   taskService,
+		taskRepo:      taskRepo,
+		projectRepo:   projectRepo,
+		mentions:      mentions,
+		notifications: notifications,
 	}
 }
 
-func (s *service) verifyProjectAccess(ctx context.Context, userID uuid.UUID, taskID int64) error {
-	_, err := s.taskService.GetByID(ctx, taskID, userID)
+// authorize checks that the parent task is visible to the user and that
+// their project role allows action
+func (s *service) authorize(ctx context.Context, userID uuid.UUID, taskID int64, action project.Action) (*project.Project, error) {
+	parent, err := s.taskService.GetByID(ctx, taskID, userID)
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") {
-			return fmt.Errorf("task not found")
-		}
-		if strings.Contains(err.Error(), "does not belong to user") {
-			return fmt.Errorf("unauthorized: no access to this")
+			return nil, fmt.Errorf("task not found")
 		}
+		return nil, err
+	}
+
+	p, _, err := s.projectRepo.Authorize(ctx, parent.ProjectID, userID, action)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// checkAssignee only lets subtasks be assigned to people in the project
+func (s *service) checkAssignee(ctx context.Context, p *project.Project, assignee *uuid.UUID) error {
+	if assignee == nil {
+		return nil
+	}
+	role, err := s.projectRepo.RoleOf(ctx, p, *assignee)
+	if err != nil {
 		return err
 	}
+	if role == "" {
+		return fmt.Errorf("assignee is not a member of this project")
+	}
 	return nil
 }
 
+// syncMentions records who the subtask's description mentions and has them
+// watch its task; like the auto-completion below it does not fail the write
+// it follows
+func (s *service) syncMentions(ctx context.Context, userID uuid.UUID, p *project.Project, subtask *Subtask, description *string) {
+	source := mention.Source{ProjectID: p.ID, TaskID: subtask.TaskID, SubtaskID: &subtask.ID}
+	mentioned, err := s.mentions.Sync(ctx, userID, source, description)
+	if err != nil {
+		log.Printf("subtask %d: %v", subtask.ID, err)
+	}
+	if len(mentioned) > 0 {
+		if err := s.taskRepo.AddWatchers(ctx, subtask.TaskID, mentioned); err != nil {
+			log.Printf("subtask %d: %v", subtask.ID, err)
+		}
+	}
+}
+
+// notify tells userIDs about a change to the subtask made by someone else
+func (s *service) notify(ctx context.Context, userID uuid.UUID, p *project.Project, subtask *Subtask,
+	notificationType string, userIDs []uuid.UUID, details map[string]interface{}) {
+	target := notification.Target{ProjectID: p.ID, TaskID: &subtask.TaskID, SubtaskID: &subtask.ID}
+	if err := s.notifications.NotifyMany(ctx, userID, userIDs, notificationType, target, details); err != nil {
+		log.Printf("subtask %d: %v", subtask.ID, err)
+	}
+}
+
+// assigned tells the new assignee about the subtask and has them watch its task
+func (s *service) assigned(ctx context.Context, userID uuid.UUID, p *project.Project, subtask *Subtask) {
+	if err := s.taskRepo.AddWatchers(ctx, subtask.TaskID, []uuid.UUID{*subtask.AssignedTo}); err != nil {
+		log.Printf("subtask %d: %v", subtask.ID, err)
+	}
+	s.notify(ctx, userID, p, subtask, notification.TypeAssigned, []uuid.UUID{*subtask.AssignedTo}, nil)
+}
+
+// notifyWatchers passes a change to the subtask on to the watchers of its task,
+// leaving out skip
+func (s *service) notifyWatchers(ctx context.Context, userID uuid.UUID, p *project.Project, subtask *Subtask,
+	notificationType string, details map[string]interface{}, skip *uuid.UUID) {
+	watchers, err := s.taskRepo.WatcherIDs(ctx, subtask.TaskID)
+	if err != nil {
+		log.Printf("subtask %d: %v", subtask.ID, err)
+		return
+	}
+	recipients := make([]uuid.UUID, 0, len(watchers))
+	for _, watcher := range watchers {
+		if skip == nil || watcher != *skip {
+			recipients = append(recipients, watcher)
+		}
+	}
+	s.notify(ctx, userID, p, subtask, notificationType, recipients, details)
+}
+
+func (s *service) notifyStatusChange(ctx context.Context, userID uuid.UUID, p *project.Project, subtask *Subtask, previousStatus string) {
+	if subtask.Status == previousStatus {
+		return
+	}
+	s.notifyWatchers(ctx, userID, p, subtask, notification.TypeStatusChanged, map[string]interface{}{
+		"from": previousStatus,
+		"to":   subtask.Status,
+	}, nil)
+}
+
 func (s *service) checkAndAutoCompleteTask(ctx context.Context, taskID int64, userID uuid.UUID) error {
 	totalCount, err := s.repo.CountByTaskID(ctx, taskID, nil)
 	if err != nil {
@@ -87,7 +179,7 @@ func (s *service) checkAndAutoCompleteTask(ctx context.Context, taskID int64, us
 
 func (s *service) List(ctx context.Context, userID uuid.UUID, taskID int64, status, priority *string) ([]Subtask, error) {
 	// Verify user has access to the parent task
-	if err := s.verifyProjectAccess(ctx, userID, taskID); err != nil {
+	if _, err := s.authorize(ctx, userID, taskID, project.ActionView); err != nil {
 		return nil, err
 	}
 
@@ -101,7 +193,12 @@ func (s *service) List(ctx context.Context, userID uuid.UUID, taskID int64, stat
 
 func (s *service) Create(ctx context.Context, userID uuid.UUID, taskID int64, req CreateSubtaskRequest) (*Subtask, error) {
 	// Verify user has access to the parent task
-	if err := s.verifyProjectAccess(ctx, userID, taskID); err != nil {
+	p, err := s.authorize(ctx, userID, taskID, project.ActionEditSubtask)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkAssignee(ctx, p, req.AssignedTo); err != nil {
 		return nil, err
 	}
 
@@ -124,6 +221,11 @@ func (s *service) Create(ctx context.Context, userID uuid.UUID, taskID int64, re
 	if err := s.repo.Create(ctx, subtask); err != nil {
 		return nil, err
 	}
+	s.syncMentions(ctx, userID, p, subtask, subtask.Description)
+	if subtask.AssignedTo != nil {
+		s.assigned(ctx, userID, p, subtask)
+	}
+	s.notifyWatchers(ctx, userID, p, subtask, notification.TypeUpdated, map[string]interface{}{"created": true}, subtask.AssignedTo)
 
 	return subtask, nil
 }
@@ -134,7 +236,7 @@ func (s *service) GetByID(ctx context.Context, userID uuid.UUID, id int64) (*Sub
 		return nil, err
 	}
 
-	if err := s.verifyProjectAccess(ctx, userID, subtask.TaskID); err != nil {
+	if _, err := s.authorize(ctx, userID, subtask.TaskID, project.ActionView); err != nil {
 		return nil, err
 	}
 
@@ -147,16 +249,31 @@ func (s *service) Update(ctx context.Context, userID uuid.UUID, id int64, req Up
 		return nil, fmt.Errorf("subtask not found - Update: %v", err)
 	}
 
-	if err := s.verifyProjectAccess(ctx, userID, subtask.TaskID); err != nil {
+	p, err := s.authorize(ctx, userID, subtask.TaskID, project.ActionEditSubtask)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkAssignee(ctx, p, req.AssignedTo); err != nil {
 		return nil, err
 	}
 
-	// Update fields if provided
+	previousStatus := subtask.Status
+	previousAssignee := subtask.AssignedTo
+
+	// Update fields if provided, noting the ones besides the status that changed
+	var changed []string
 	if req.Title != nil && *req.Title != "" {
+		if *req.Title != subtask.Title {
+			changed = append(changed, "title")
+		}
 		subtask.Title = *req.Title
 	}
 
 	if req.Description != nil {
+		if subtask.Description == nil || *req.Description != *subtask.Description {
+			changed = append(changed, "description")
+		}
 		subtask.Description = req.Description
 	}
 
@@ -166,14 +283,25 @@ func (s *service) Update(ctx context.Context, userID uuid.UUID, id int64, req Up
 	}
 
 	if req.Priority != nil {
+		if subtask.Priority == nil || *req.Priority != *subtask.Priority {
+			changed = append(changed, "priority")
+		}
 		subtask.Priority = req.Priority
 	}
 
 	if req.DueDate != nil {
+		if subtask.DueDate == nil || !req.DueDate.Equal(*subtask.DueDate) {
+			changed = append(changed, "due_date")
+		}
 		subtask.DueDate = req.DueDate
 	}
 
+	var newAssignee *uuid.UUID
 	if req.AssignedTo != nil {
+		if previousAssignee == nil || *previousAssignee != *req.AssignedTo {
+			changed = append(changed, "assigned_to")
+			newAssignee = req.AssignedTo
+		}
 		subtask.AssignedTo = req.AssignedTo
 	}
 
@@ -182,6 +310,14 @@ func (s *service) Update(ctx context.Context, userID uuid.UUID, id int64, req Up
 	if err := s.repo.Update(ctx, subtask); err != nil {
 		return nil, err
 	}
+	s.syncMentions(ctx, userID, p, subtask, req.Description)
+	if newAssignee != nil {
+		s.assigned(ctx, userID, p, subtask)
+	}
+	s.notifyStatusChange(ctx, userID, p, subtask, previousStatus)
+	if len(changed) > 0 {
+		s.notifyWatchers(ctx, userID, p, subtask, notification.TypeUpdated, map[string]interface{}{"fields": changed}, newAssignee)
+	}
 
 	// Check if all subtasks are completed to auto-complete parent task
 	if err := s.checkAndAutoCompleteTask(ctx, subtask.TaskID, userID); err != nil {
@@ -198,11 +334,13 @@ func (s *service) ToggleComplete(ctx context.Context, userID uuid.UUID, id int64
 		return nil, err
 	}
 
-	if err := s.verifyProjectAccess(ctx, userID, subtask.TaskID); err != nil {
+	p, err := s.authorize(ctx, userID, subtask.TaskID, project.ActionEditSubtask)
+	if err != nil {
 		return nil, err
 	}
 
 	// Toggle completion status
+	previousStatus := subtask.Status
 	subtask.Completed = !subtask.Completed
 	if subtask.Completed {
 		subtask.Status = StatusCompleted
@@ -215,6 +353,7 @@ func (s *service) ToggleComplete(ctx context.Context, userID uuid.UUID, id int64
 	if err := s.repo.Update(ctx, subtask); err != nil {
 		return nil, err
 	}
+	s.notifyStatusChange(ctx, userID, p, subtask, previousStatus)
 
 	// check if all subtasks are completed to auto-complete parent task
 	if err := s.checkAndAutoCompleteTask(ctx, subtask.TaskID, userID); err != nil {
@@ -231,7 +370,7 @@ func (s *service) Delete(ctx context.Context, userID uuid.UUID, id int64) error
 		return err
 	}
 
-	if err := s.verifyProjectAccess(ctx, userID, subtask.TaskID); err != nil {
+	if _, err := s.authorize(ctx, userID, subtask.TaskID, project.ActionDeleteSubtask); err != nil {
 		return err
 	}
 
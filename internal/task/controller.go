@@ -2,6 +2,7 @@ package task
 
 import (
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -43,13 +44,13 @@ func (ctrl *Controller) List(c *gin.Context) {
 
 	tasks, err := ctrl.service.List(c.Request.Context(), projectID, userUUID, filters)
 	if err != nil {
-		if err.Error() == "project not found" {
+		if strings.Contains(err.Error(), "not found") {
 			c.IndentedJSON(404, gin.H{
 				"error": err.Error(),
 			})
 			return
 		}
-		if err.Error() == "unauthorized: you don't own this project" {
+		if strings.Contains(err.Error(), "unauthorized") {
 			c.IndentedJSON(403, gin.H{
 				"error": err.Error(),
 			})
@@ -92,18 +93,30 @@ func (ctrl *Controller) Create(c *gin.Context) {
 
 	task, err := ctrl.service.Create(c.Request.Context(), projectID, userUUID, &dto)
 	if err != nil {
-		if err.Error() == "project not found" {
+		if strings.Contains(err.Error(), "not found") {
 			c.IndentedJSON(404, gin.H{
 				"error": err.Error(),
 			})
 			return
 		}
-		if err.Error() == "unauthorized: you don't own this project" {
+		if strings.Contains(err.Error(), "not a member") {
+			c.IndentedJSON(422, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		if strings.Contains(err.Error(), "unauthorized") {
 			c.IndentedJSON(403, gin.H{
 				"error": err.Error(),
 			})
 			return
 		}
+		if strings.Contains(err.Error(), "archived") {
+			c.IndentedJSON(409, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
 		c.IndentedJSON(500, gin.H{
 			"error": err.Error(),
 		})
@@ -133,13 +146,13 @@ func (ctrl *Controller) GetByID(c *gin.Context) {
 
 	task, err := ctrl.service.GetByID(c.Request.Context(), taskID, userUUID)
 	if err != nil {
-		if err.Error() == "task not found" {
+		if strings.Contains(err.Error(), "not found") {
 			c.IndentedJSON(404, gin.H{
 				"error": err.Error(),
 			})
 			return
 		}
-		if err.Error() == "unauthorized: you don't own this project" {
+		if strings.Contains(err.Error(), "unauthorized") {
 			c.IndentedJSON(403, gin.H{
 				"error": err.Error(),
 			})
@@ -182,18 +195,30 @@ func (ctrl *Controller) Update(c *gin.Context) {
 
 	task, err := ctrl.service.Update(c.Request.Context(), taskID, userUUID, &dto)
 	if err != nil {
-		if err.Error() == "task not found" {
+		if strings.Contains(err.Error(), "not found") {
 			c.IndentedJSON(404, gin.H{
 				"error": err.Error(),
 			})
 			return
 		}
-		if err.Error() == "unauthorized: you don't own this project" {
+		if strings.Contains(err.Error(), "not a member") {
+			c.IndentedJSON(422, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		if strings.Contains(err.Error(), "unauthorized") {
 			c.IndentedJSON(403, gin.H{
 				"error": err.Error(),
 			})
 			return
 		}
+		if strings.Contains(err.Error(), "archived") {
+			c.IndentedJSON(409, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
 		c.IndentedJSON(500, gin.H{
 			"error": err.Error(),
 		})
@@ -223,18 +248,24 @@ func (ctrl *Controller) ToggleComplete(c *gin.Context) {
 
 	task, err := ctrl.service.ToggleComplete(c.Request.Context(), taskID, userUUID)
 	if err != nil {
-		if err.Error() == "task not found" {
+		if strings.Contains(err.Error(), "not found") {
 			c.IndentedJSON(404, gin.H{
 				"error": err.Error(),
 			})
 			return
 		}
-		if err.Error() == "unauthorized: you don't own this project" {
+		if strings.Contains(err.Error(), "unauthorized") {
 			c.IndentedJSON(403, gin.H{
 				"error": err.Error(),
 			})
 			return
 		}
+		if strings.Contains(err.Error(), "archived") {
+			c.IndentedJSON(409, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
 		c.IndentedJSON(500, gin.H{
 			"error": err.Error(),
 		})
@@ -264,18 +295,24 @@ func (ctrl *Controller) Delete(c *gin.Context) {
 
 	err = ctrl.service.Delete(c.Request.Context(), taskID, userUUID)
 	if err != nil {
-		if err.Error() == "task not found" {
+		if strings.Contains(err.Error(), "not found") {
 			c.IndentedJSON(404, gin.H{
 				"error": err.Error(),
 			})
 			return
 		}
-		if err.Error() == "unauthorized: you don't own this project" {
+		if strings.Contains(err.Error(), "unauthorized") {
 			c.IndentedJSON(403, gin.H{
 				"error": err.Error(),
 			})
 			return
 		}
+		if strings.Contains(err.Error(), "archived") {
+			c.IndentedJSON(409, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
 		c.IndentedJSON(500, gin.H{
 			"error": err.Error(),
 		})
@@ -284,3 +321,126 @@ func (ctrl *Controller) Delete(c *gin.Context) {
 
 	c.IndentedJSON(200, gin.H{"message": "Task deleted successfully"})
 }
+
+func (ctrl *Controller) Watch(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.IndentedJSON(401, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+
+	taskID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.IndentedJSON(400, gin.H{
+			"error": "invalid task ID",
+		})
+		return
+	}
+
+	err = ctrl.service.Watch(c.Request.Context(), taskID, userUUID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.IndentedJSON(404, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		if strings.Contains(err.Error(), "unauthorized") {
+			c.IndentedJSON(403, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		c.IndentedJSON(500, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.IndentedJSON(200, gin.H{"message": "Watching task"})
+}
+
+func (ctrl *Controller) Unwatch(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.IndentedJSON(401, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+
+	taskID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.IndentedJSON(400, gin.H{
+			"error": "invalid task ID",
+		})
+		return
+	}
+
+	err = ctrl.service.Unwatch(c.Request.Context(), taskID, userUUID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "not watching") {
+			c.IndentedJSON(404, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		if strings.Contains(err.Error(), "unauthorized") {
+			c.IndentedJSON(403, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		c.IndentedJSON(500, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.IndentedJSON(200, gin.H{"message": "Stopped watching task"})
+}
+
+func (ctrl *Controller) Watchers(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.IndentedJSON(401, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+
+	taskID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.IndentedJSON(400, gin.H{
+			"error": "invalid task ID",
+		})
+		return
+	}
+
+	watchers, err := ctrl.service.Watchers(c.Request.Context(), taskID, userUUID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.IndentedJSON(404, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		if strings.Contains(err.Error(), "unauthorized") {
+			c.IndentedJSON(403, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		c.IndentedJSON(500, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.IndentedJSON(200, watchers)
+}
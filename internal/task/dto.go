@@ -2,6 +2,8 @@ package task
 
 import (
 	"time"
+
+	"github.com/google/uuid"
 )
 
 type CreateTaskRequest struct {
@@ -10,6 +12,8 @@ type CreateTaskRequest struct {
 	Status      string     `json:"status" binding:"required,oneof=todo in_progress completed"`
 	Priority    *string    `json:"priority" binding:"omitempty,oneof=low medium high"`
 	DueDate     *time.Time `json:"due_date"`
+	// Assignees must all be in the project
+	Assignees []uuid.UUID `json:"assignees" binding:"omitempty,max=20,unique"`
 }
 
 type UpdateTaskRequest struct {
@@ -18,34 +22,45 @@ type UpdateTaskRequest struct {
 	Status      string     `json:"status" binding:"omitempty,oneof=todo in_progress completed"`
 	Priority    *string    `json:"priority" binding:"omitempty,oneof=low medium high"`
 	DueDate     *time.Time `json:"due_date"`
+	// Assignees replaces the current assignees when given; an empty list
+	// unassigns everyone
+	Assignees *[]uuid.UUID `json:"assignees" binding:"omitempty,max=20,unique"`
 }
 
 type TaskResponse struct {
-	ID          int64      `json:"id"`
-	ProjectID   int64      `json:"project_id"`
-	Title       string     `json:"title"`
-	Description *string    `json:"description"`
-	Status      string     `json:"status"`
-	Priority    *string    `json:"priority"`
-	DueDate     *time.Time `json:"due_date"`
-	Completed   bool       `json:"completed"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
+	ID           int64       `json:"id"`
+	ProjectID    int64       `json:"project_id"`
+	Title        string      `json:"title"`
+	Description  *string     `json:"description"`
+	Status       string      `json:"status"`
+	Priority     *string     `json:"priority"`
+	DueDate      *time.Time  `json:"due_date"`
+	Completed    bool        `json:"completed"`
+	Assignees    []uuid.UUID `json:"assignees"`
+	CommentCount int64       `json:"comment_count"`
+	CreatedAt    time.Time   `json:"created_at"`
+	UpdatedAt    time.Time   `json:"updated_at"`
 }
 
 func ToTaskResponse(task *Task) *TaskResponse {
-	return &TaskResponse{
-		ID:          task.ID,
-		ProjectID:   task.ProjectID,
-		Title:       task.Title,
-		Description: task.Description,
-		Status:      task.Status,
-		Priority:    task.Priority,
-		DueDate:     task.DueDate,
-		Completed:   task.Completed,
-		CreatedAt:   task.CreatedAt,
-		UpdatedAt:   task.UpdatedAt,
+	res := &TaskResponse{
+		ID:           task.ID,
+		ProjectID:    task.ProjectID,
+		Title:        task.Title,
+		Description:  task.Description,
+		Status:       task.Status,
+		Priority:     task.Priority,
+		DueDate:      task.DueDate,
+		Completed:    task.Completed,
+		Assignees:    task.Assignees,
+		CommentCount: task.CommentCount,
+		CreatedAt:    task.CreatedAt,
+		UpdatedAt:    task.UpdatedAt,
 	}
+	if res.Assignees == nil {
+		res.Assignees = []uuid.UUID{}
+	}
+	return res
 }
 
 func ToTaskResponseList(tasks []*Task) []*TaskResponse {
@@ -55,3 +70,11 @@ func ToTaskResponseList(tasks []*Task) []*TaskResponse {
 	}
 	return responses
 }
+
+type WatcherResponse struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Handle    *string   `json:"handle"`
+	CreatedAt time.Time `json:"watching_since"`
+}
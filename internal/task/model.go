@@ -2,6 +2,9 @@ package task
 
 import (
 	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type Task struct {
@@ -15,12 +18,40 @@ type Task struct {
 	Completed   bool       `gorm:"default:false;not null"`
 	CreatedAt   time.Time  `gorm:"type:timestamp;not null"`
 	UpdatedAt   time.Time  `gorm:"type:timestamp;not null"`
+	// DeletedAt equals the project's when the task was trashed along with it
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+	// Assignees and CommentCount are loaded by the repository
+	Assignees    []uuid.UUID `gorm:"-"`
+	CommentCount int64       `gorm:"-"`
 }
 
 func (Task) TableName() string {
 	return "task"
 }
 
+type TaskAssignee struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement"`
+	TaskID    int64     `gorm:"not null"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null"`
+	CreatedAt time.Time `gorm:"type:timestamp;not null"`
+}
+
+func (TaskAssignee) TableName() string {
+	return "task_assignee"
+}
+
+// TaskWatcher is someone who gets notified when the task or its subtasks change
+type TaskWatcher struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement"`
+	TaskID    int64     `gorm:"not null"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null"`
+	CreatedAt time.Time `gorm:"type:timestamp;not null"`
+}
+
+func (TaskWatcher) TableName() string {
+	return "task_watcher"
+}
+
 // Valid status values
 const (
 	StatusTodo       = "todo"
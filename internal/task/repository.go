@@ -4,8 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type Repository struct {
@@ -25,6 +28,9 @@ func (r *Repository) FindByID(ctx context.Context, id int64) (*Task, error) {
 		}
 		return nil, err
 	}
+	if err := r.LoadDetails(ctx, []*Task{&task}); err != nil {
+		return nil, fmt.Errorf("FindByID: %v", err)
+	}
 	return &task, nil
 }
 
@@ -43,17 +49,142 @@ func (r *Repository) FindByProjectID(ctx context.Context, projectID int64, filte
 	if err != nil {
 		return nil, fmt.Errorf("FindByProjectID: %v", err)
 	}
+	if err := r.LoadDetails(ctx, tasks); err != nil {
+		return nil, fmt.Errorf("FindByProjectID: %v", err)
+	}
 	return tasks, nil
 }
 
+// LoadDetails fills in the assignees and comment counts of the given tasks
+func (r *Repository) LoadDetails(ctx context.Context, tasks []*Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	byID := make(map[int64]*Task, len(tasks))
+	ids := make([]int64, len(tasks))
+	for i, t := range tasks {
+		byID[t.ID] = t
+		ids[i] = t.ID
+		t.Assignees = []uuid.UUID{}
+	}
+
+	var assignees []TaskAssignee
+	err := r.db.WithContext(ctx).Where("task_id IN ?", ids).Order("id").Find(&assignees).Error
+	if err != nil {
+		return fmt.Errorf("LoadDetails: %v", err)
+	}
+	for _, a := range assignees {
+		byID[a.TaskID].Assignees = append(byID[a.TaskID].Assignees, a.UserID)
+	}
+
+	// comments on the task itself, not on its subtasks, without tombstones
+	var counts []struct {
+		TaskID int64
+		Count  int64
+	}
+	err = r.db.WithContext(ctx).Table("comment").Select("task_id, COUNT(*) AS count").
+		Where("task_id IN ? AND subtask_id IS NULL AND deleted_at IS NULL", ids).Group("task_id").Scan(&counts).Error
+	if err != nil {
+		return fmt.Errorf("LoadDetails: %v", err)
+	}
+	for _, c := range counts {
+		byID[c.TaskID].CommentCount = c.Count
+	}
+	return nil
+}
+
+// Create inserts the task together with its assignees
 func (r *Repository) Create(ctx context.Context, task *Task) error {
-	err := r.db.WithContext(ctx).Create(task).Error
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(task).Error; err != nil {
+			return err
+		}
+		return insertAssignees(tx, task.ID, task.Assignees)
+	})
 	if err != nil {
 		return fmt.Errorf("task - Create: %v", err)
 	}
 	return nil
 }
 
+// SetAssignees replaces the task's assignees
+func (r *Repository) SetAssignees(ctx context.Context, taskID int64, userIDs []uuid.UUID) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("task_id = ?", taskID).Delete(&TaskAssignee{}).Error; err != nil {
+			return err
+		}
+		return insertAssignees(tx, taskID, userIDs)
+	})
+	if err != nil {
+		return fmt.Errorf("SetAssignees: %v", err)
+	}
+	return nil
+}
+
+func insertAssignees(tx *gorm.DB, taskID int64, userIDs []uuid.UUID) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	now := time.Now()
+	rows := make([]TaskAssignee, len(userIDs))
+	for i, userID := range userIDs {
+		rows[i] = TaskAssignee{TaskID: taskID, UserID: userID, CreatedAt: now}
+	}
+	return tx.Create(&rows).Error
+}
+
+// AddWatchers makes the users watch the task; those watching already are skipped
+func (r *Repository) AddWatchers(ctx context.Context, taskID int64, userIDs []uuid.UUID) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	now := time.Now()
+	rows := make([]TaskWatcher, len(userIDs))
+	for i, userID := range userIDs {
+		rows[i] = TaskWatcher{TaskID: taskID, UserID: userID, CreatedAt: now}
+	}
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error
+	if err != nil {
+		return fmt.Errorf("AddWatchers: %v", err)
+	}
+	return nil
+}
+
+// RemoveWatcher reports false when the user was not watching the task
+func (r *Repository) RemoveWatcher(ctx context.Context, taskID int64, userID uuid.UUID) (bool, error) {
+	res := r.db.WithContext(ctx).Where("task_id = ? AND user_id = ?", taskID, userID).Delete(&TaskWatcher{})
+	if res.Error != nil {
+		return false, fmt.Errorf("RemoveWatcher: %v", res.Error)
+	}
+	return res.RowsAffected > 0, nil
+}
+
+func (r *Repository) WatcherIDs(ctx context.Context, taskID int64) ([]uuid.UUID, error) {
+	var userIDs []uuid.UUID
+	err := r.db.WithContext(ctx).Model(&TaskWatcher{}).Where("task_id = ?", taskID).
+		Order("id").Pluck("user_id", &userIDs).Error
+	if err != nil {
+		return nil, fmt.Errorf("WatcherIDs: %v", err)
+	}
+	return userIDs, nil
+}
+
+// ListWatchers returns the task's watchers with their names and emails, in
+// the order they started watching
+func (r *Repository) ListWatchers(ctx context.Context, taskID int64) ([]*WatcherResponse, error) {
+	watchers := []*WatcherResponse{}
+	err := r.db.WithContext(ctx).Table("task_watcher").
+		Select("task_watcher.user_id, app_user.name, app_user.email, app_user.handle, task_watcher.created_at").
+		Joins("JOIN app_user ON app_user.id = task_watcher.user_id").
+		Where("task_watcher.task_id = ?", taskID).
+		Order("task_watcher.id").
+		Scan(&watchers).Error
+	if err != nil {
+		return nil, fmt.Errorf("ListWatchers: %v", err)
+	}
+	return watchers, nil
+}
+
 func (r *Repository) Update(ctx context.Context, task *Task) error {
 	err := r.db.WithContext(ctx).Save(task).Error
 	if err != nil {
@@ -62,8 +193,16 @@ func (r *Repository) Update(ctx context.Context, task *Task) error {
 	return nil
 }
 
+// Delete moves the task and its subtasks to the trash with one shared deleted_at
 func (r *Repository) Delete(ctx context.Context, id int64) error {
-	err := r.db.WithContext(ctx).Delete(&Task{}, id).Error
+	now := time.Now()
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec("UPDATE subtask SET deleted_at = ? WHERE task_id = ? AND deleted_at IS NULL", now, id).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&Task{}).Where("id = ?", id).Update("deleted_at", now).Error
+	})
 	if err != nil {
 		return fmt.Errorf("task - Delete: %v", err)
 	}
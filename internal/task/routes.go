@@ -5,9 +5,10 @@ import (
 )
 
 func RegisterRoutes(group *gin.RouterGroup, ctrl *Controller,
-	 authMw gin.HandlerFunc, rateLimitMw gin.HandlerFunc) {
+	 authMw gin.HandlerFunc, scopeMw gin.HandlerFunc, rateLimitMw gin.HandlerFunc) {
 	projects := group.Group("/projects")
 	projects.Use(authMw)
+	projects.Use(scopeMw)
 	projects.Use(rateLimitMw)
 	{
 		projects.GET("/:id/tasks", ctrl.List)
@@ -16,11 +17,15 @@ func RegisterRoutes(group *gin.RouterGroup, ctrl *Controller,
 
 	tasks := group.Group("/tasks")
 	tasks.Use(authMw)
+	tasks.Use(scopeMw)
 	tasks.Use(rateLimitMw)
 	{
 		tasks.GET("/:id", ctrl.GetByID)
 		tasks.PUT("/:id", ctrl.Update)
 		tasks.DELETE("/:id", ctrl.Delete)
 		tasks.PATCH("/:id/complete", ctrl.ToggleComplete)
+		tasks.GET("/:id/watchers", ctrl.Watchers)
+		tasks.POST("/:id/watch", ctrl.Watch)
+		tasks.DELETE("/:id/watch", ctrl.Unwatch)
 	}
 }
@@ -3,8 +3,11 @@ package task
 import (
 	"context"
 	"fmt"
+	"log"
 	"time"
 
+	"task-management/internal/mention"
+	"task-management/internal/notification"
 	"task-management/internal/project"
 
 	"github.com/google/uuid"
@@ -17,44 +20,117 @@ type Service interface {
 	Update(ctx context.Context, taskID int64, userID uuid.UUID, dto *UpdateTaskRequest) (*TaskResponse, error)
 	ToggleComplete(ctx context.Context, taskID int64, userID uuid.UUID) (*TaskResponse, error)
 	Delete(ctx context.Context, taskID int64, userID uuid.UUID) error
+	// Watch and Unwatch start and stop the user's notifications about changes
+	// to the task and its subtasks
+	Watch(ctx context.Context, taskID int64, userID uuid.UUID) error
+	Unwatch(ctx context.Context, taskID int64, userID uuid.UUID) error
+	Watchers(ctx context.Context, taskID int64, userID uuid.UUID) ([]*WatcherResponse, error)
 }
 
 type service struct {
-	repo        *Repository
-	projectRepo *project.Repository
+	repo          *Repository
+	projectRepo   *project.Repository
+	mentions      mention.Service
+	notifications notification.Service
 }
 
-func NewService(repo *Repository, projectRepo *project.Repository) Service {
+func NewService(repo *Repository, projectRepo *project.Repository, mentions mention.Service,
+	notifications notification.Service) Service {
 	return &service{
-		repo:        repo,
-		projectRepo: projectRepo,
+		repo:          repo,
+		projectRepo:   projectRepo,
+		mentions:      mentions,
+		notifications: notifications,
 	}
 }
 
-func (s *service) verifyProjectOwner(ctx context.Context, projectID int64, userID uuid.UUID) error {
-	hasAccess, err := s.projectRepo.IsOwner(ctx, projectID, userID)
+// syncMentions records the mentions in the task's description and has the
+// newly mentioned users watch the task. The task is saved already, so
+// failing to do so is only logged.
+func (s *service) syncMentions(ctx context.Context, userID uuid.UUID, task *Task, description *string) {
+	source := mention.Source{ProjectID: task.ProjectID, TaskID: task.ID}
+	mentioned, err := s.mentions.Sync(ctx, userID, source, description)
 	if err != nil {
-		return fmt.Errorf("verifyProjectAccess: %v", err)
+		log.Printf("task %d: %v", task.ID, err)
 	}
-	if !hasAccess {
-		return fmt.Errorf("verifyProjectAccess: unauthorized: you are not the owner of this project")
+	if len(mentioned) > 0 {
+		s.watch(ctx, task, mentioned...)
 	}
-	return nil
 }
 
-func (s *service) verifyProjectAccess(ctx context.Context, projectID int64, userID uuid.UUID) error {
-	hasAccess, err := s.projectRepo.HasAccess(ctx, projectID, userID)
+// authorize checks the user's project role against the permission matrix
+func (s *service) authorize(ctx context.Context, projectID int64, userID uuid.UUID, action project.Action) (*project.Project, error) {
+	p, _, err := s.projectRepo.Authorize(ctx, projectID, userID, action)
 	if err != nil {
-		return fmt.Errorf("verifyProjectAccess: %v", err)
+		return nil, fmt.Errorf("authorize: %v", err)
 	}
-	if !hasAccess {
-		return fmt.Errorf("verifyProjectAccess: unauthorized: you don't have access to this project")
+	return p, nil
+}
+
+// checkAssignees only lets tasks be assigned to the owner and members
+func (s *service) checkAssignees(ctx context.Context, p *project.Project, assignees []uuid.UUID) error {
+	for _, assignee := range assignees {
+		role, err := s.projectRepo.RoleOf(ctx, p, assignee)
+		if err != nil {
+			return err
+		}
+		if role == "" {
+			return fmt.Errorf("assignee %s is not a member of this project", assignee)
+		}
 	}
 	return nil
 }
 
+// notify tells each of userIDs about a change to the task. Like mentions,
+// notifications never fail the change itself.
+func (s *service) notify(ctx context.Context, actorID uuid.UUID, task *Task, notificationType string,
+	userIDs []uuid.UUID, details map[string]interface{}) {
+	target := notification.Target{ProjectID: task.ProjectID, TaskID: &task.ID}
+	if err := s.notifications.NotifyMany(ctx, actorID, userIDs, notificationType, target, details); err != nil {
+		log.Printf("task %d: %v", task.ID, err)
+	}
+}
+
+// watch makes the users watch the task as a side effect of another change
+func (s *service) watch(ctx context.Context, task *Task, userIDs ...uuid.UUID) {
+	if err := s.repo.AddWatchers(ctx, task.ID, userIDs); err != nil {
+		log.Printf("task %d: %v", task.ID, err)
+	}
+}
+
+// notifyWatchers tells the task's watchers, except those in skip, about a change
+func (s *service) notifyWatchers(ctx context.Context, actorID uuid.UUID, task *Task, notificationType string,
+	details map[string]interface{}, skip []uuid.UUID) {
+	watchers, err := s.repo.WatcherIDs(ctx, task.ID)
+	if err != nil {
+		log.Printf("task %d: %v", task.ID, err)
+		return
+	}
+	skipped := map[uuid.UUID]bool{}
+	for _, userID := range skip {
+		skipped[userID] = true
+	}
+	recipients := make([]uuid.UUID, 0, len(watchers))
+	for _, userID := range watchers {
+		if !skipped[userID] {
+			recipients = append(recipients, userID)
+		}
+	}
+	s.notify(ctx, actorID, task, notificationType, recipients, details)
+}
+
+func (s *service) notifyStatusChange(ctx context.Context, actorID uuid.UUID, task *Task, previousStatus string) {
+	if task.Status == previousStatus {
+		return
+	}
+	s.notifyWatchers(ctx, actorID, task, notification.TypeStatusChanged, map[string]interface{}{
+		"from": previousStatus,
+		"to":   task.Status,
+	}, nil)
+}
+
 func (s *service) List(ctx context.Context, projectID int64, userID uuid.UUID, filters map[string]interface{}) ([]*TaskResponse, error) {
-	if err := s.verifyProjectAccess(ctx, projectID, userID); err != nil {
+	if _, err := s.authorize(ctx, projectID, userID, project.ActionView); err != nil {
 		return nil, err
 	}
 
@@ -66,7 +142,12 @@ func (s *service) List(ctx context.Context, projectID int64, userID uuid.UUID, f
 }
 
 func (s *service) Create(ctx context.Context, projectID int64, userID uuid.UUID, dto *CreateTaskRequest) (*TaskResponse, error) {
-	if err := s.verifyProjectAccess(ctx, projectID, userID); err != nil {
+	p, err := s.authorize(ctx, projectID, userID, project.ActionCreateTask)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkAssignees(ctx, p, dto.Assignees); err != nil {
 		return nil, err
 	}
 
@@ -81,11 +162,15 @@ func (s *service) Create(ctx context.Context, projectID int64, userID uuid.UUID,
 		Completed:   dto.Status == StatusCompleted,
 		CreatedAt:   now,
 		UpdatedAt:   now,
+		Assignees:   dto.Assignees,
 	}
 
 	if err := s.repo.Create(ctx, task); err != nil {
 		return nil, err
 	}
+	s.watch(ctx, task, append([]uuid.UUID{userID}, task.Assignees...)...)
+	s.syncMentions(ctx, userID, task, task.Description)
+	s.notify(ctx, userID, task, notification.TypeAssigned, task.Assignees, nil)
 
 	return ToTaskResponse(task), nil
 }
@@ -96,7 +181,7 @@ func (s *service) GetByID(ctx context.Context, taskID int64, userID uuid.UUID) (
 		return nil, fmt.Errorf("GetByID: %v", err)
 	}
 
-	if err := s.verifyProjectAccess(ctx, task.ProjectID, userID); err != nil {
+	if _, err := s.authorize(ctx, task.ProjectID, userID, project.ActionView); err != nil {
 		return nil, fmt.Errorf("GetByID: %v", err)
 	}
 
@@ -109,14 +194,35 @@ func (s *service) Update(ctx context.Context, taskID int64, userID uuid.UUID, dt
 		return nil, fmt.Errorf("Update: %v", err)
 	}
 
-	if err := s.verifyProjectOwner(ctx, task.ProjectID, userID); err != nil {
+	p, err := s.authorize(ctx, task.ProjectID, userID, project.ActionEditTask)
+	if err != nil {
 		return nil, fmt.Errorf("Update: %v", err)
 	}
 
+	if dto.Assignees != nil {
+		if err := s.checkAssignees(ctx, p, *dto.Assignees); err != nil {
+			return nil, fmt.Errorf("Update: %v", err)
+		}
+	}
+
+	previousStatus := task.Status
+	previousAssignees := map[uuid.UUID]bool{}
+	for _, assignee := range task.Assignees {
+		previousAssignees[assignee] = true
+	}
+
+	// changed lists the fields watchers are told about besides the status
+	var changed []string
 	if dto.Title != "" {
+		if dto.Title != task.Title {
+			changed = append(changed, "title")
+		}
 		task.Title = dto.Title
 	}
 	if dto.Description != nil {
+		if task.Description == nil || *dto.Description != *task.Description {
+			changed = append(changed, "description")
+		}
 		task.Description = dto.Description
 	}
 	if dto.Status != "" {
@@ -124,9 +230,15 @@ func (s *service) Update(ctx context.Context, taskID int64, userID uuid.UUID, dt
 		task.Completed = dto.Status == StatusCompleted
 	}
 	if dto.Priority != nil {
+		if task.Priority == nil || *dto.Priority != *task.Priority {
+			changed = append(changed, "priority")
+		}
 		task.Priority = dto.Priority
 	}
 	if dto.DueDate != nil {
+		if task.DueDate == nil || !dto.DueDate.Equal(*task.DueDate) {
+			changed = append(changed, "due_date")
+		}
 		task.DueDate = dto.DueDate
 	}
 	task.UpdatedAt = time.Now()
@@ -135,6 +247,31 @@ func (s *service) Update(ctx context.Context, taskID int64, userID uuid.UUID, dt
 		return nil, fmt.Errorf("Update: %v", err)
 	}
 
+	if dto.Assignees != nil {
+		if err := s.repo.SetAssignees(ctx, task.ID, *dto.Assignees); err != nil {
+			return nil, fmt.Errorf("Update: %v", err)
+		}
+		task.Assignees = *dto.Assignees
+	}
+	s.syncMentions(ctx, userID, task, dto.Description)
+
+	var added []uuid.UUID
+	for _, assignee := range task.Assignees {
+		if !previousAssignees[assignee] {
+			added = append(added, assignee)
+		}
+	}
+	if len(added) > 0 || len(task.Assignees) != len(previousAssignees) {
+		changed = append(changed, "assignees")
+	}
+	s.watch(ctx, task, added...)
+	s.notify(ctx, userID, task, notification.TypeAssigned, added, nil)
+	s.notifyStatusChange(ctx, userID, task, previousStatus)
+	if len(changed) > 0 {
+		// the new assignees have just been told about the task
+		s.notifyWatchers(ctx, userID, task, notification.TypeUpdated, map[string]interface{}{"fields": changed}, added)
+	}
+
 	return ToTaskResponse(task), nil
 }
 
@@ -144,10 +281,11 @@ func (s *service) ToggleComplete(ctx context.Context, taskID int64, userID uuid.
 		return nil, fmt.Errorf("ToggleComplete: %v", err)
 	}
 
-	if err := s.verifyProjectAccess(ctx, task.ProjectID, userID); err != nil {
+	if _, err := s.authorize(ctx, task.ProjectID, userID, project.ActionEditTask); err != nil {
 		return nil, err
 	}
 
+	previousStatus := task.Status
 	task.Completed = !task.Completed
 
 	if task.Completed {
@@ -161,6 +299,7 @@ func (s *service) ToggleComplete(ctx context.Context, taskID int64, userID uuid.
 	if err := s.repo.Update(ctx, task); err != nil {
 		return nil, err
 	}
+	s.notifyStatusChange(ctx, userID, task, previousStatus)
 
 	return ToTaskResponse(task), nil
 }
@@ -171,9 +310,55 @@ func (s *service) Delete(ctx context.Context, taskID int64, userID uuid.UUID) er
 		return err
 	}
 
-	if err := s.verifyProjectOwner(ctx, task.ProjectID, userID); err != nil {
+	if _, err := s.authorize(ctx, task.ProjectID, userID, project.ActionDeleteTask); err != nil {
 		return err
 	}
 
 	return s.repo.Delete(ctx, taskID)
 }
+
+func (s *service) Watch(ctx context.Context, taskID int64, userID uuid.UUID) error {
+	task, err := s.repo.FindByID(ctx, taskID)
+	if err != nil {
+		return fmt.Errorf("Watch: %v", err)
+	}
+
+	if _, err := s.authorize(ctx, task.ProjectID, userID, project.ActionView); err != nil {
+		return fmt.Errorf("Watch: %v", err)
+	}
+
+	return s.repo.AddWatchers(ctx, task.ID, []uuid.UUID{userID})
+}
+
+func (s *service) Unwatch(ctx context.Context, taskID int64, userID uuid.UUID) error {
+	task, err := s.repo.FindByID(ctx, taskID)
+	if err != nil {
+		return fmt.Errorf("Unwatch: %v", err)
+	}
+
+	if _, err := s.authorize(ctx, task.ProjectID, userID, project.ActionView); err != nil {
+		return fmt.Errorf("Unwatch: %v", err)
+	}
+
+	removed, err := s.repo.RemoveWatcher(ctx, task.ID, userID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return fmt.Errorf("not watching this task")
+	}
+	return nil
+}
+
+func (s *service) Watchers(ctx context.Context, taskID int64, userID uuid.UUID) ([]*WatcherResponse, error) {
+	task, err := s.repo.FindByID(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("Watchers: %v", err)
+	}
+
+	if _, err := s.authorize(ctx, task.ProjectID, userID, project.ActionView); err != nil {
+		return nil, fmt.Errorf("Watchers: %v", err)
+	}
+
+	return s.repo.ListWatchers(ctx, task.ID)
+}
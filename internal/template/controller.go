@@ -0,0 +1,161 @@
+package template
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type Controller struct {
+	service Service
+}
+
+func NewController(service Service) *Controller {
+	return &Controller{service: service}
+}
+
+func (controller *Controller) Create(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.IndentedJSON(401, gin.H{"error": "unauthorized"})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+
+	var req CreateTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.IndentedJSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	template, err := controller.service.Create(c.Request.Context(), userUUID, &req)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.IndentedJSON(404, gin.H{"error": err.Error()})
+			return
+		}
+		if strings.Contains(err.Error(), "unauthorized") {
+			c.IndentedJSON(403, gin.H{"error": err.Error()})
+			return
+		}
+		c.IndentedJSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.IndentedJSON(201, template)
+}
+
+func (controller *Controller) List(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.IndentedJSON(401, gin.H{"error": "unauthorized"})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+
+	templates, err := controller.service.List(c.Request.Context(), userUUID)
+	if err != nil {
+		c.IndentedJSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.IndentedJSON(200, templates)
+}
+
+func (controller *Controller) GetByID(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.IndentedJSON(401, gin.H{"error": "unauthorized"})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.IndentedJSON(400, gin.H{"error": "invalid template id"})
+		return
+	}
+
+	template, err := controller.service.GetByID(c.Request.Context(), userUUID, id)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.IndentedJSON(404, gin.H{"error": err.Error()})
+			return
+		}
+		c.IndentedJSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.IndentedJSON(200, template)
+}
+
+func (controller *Controller) Delete(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.IndentedJSON(401, gin.H{"error": "unauthorized"})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.IndentedJSON(400, gin.H{"error": "invalid template id"})
+		return
+	}
+
+	if err := controller.service.Delete(c.Request.Context(), userUUID, id); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.IndentedJSON(404, gin.H{"error": err.Error()})
+			return
+		}
+		c.IndentedJSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.IndentedJSON(200, gin.H{"message": "template deleted"})
+}
+
+// Clone copies a project the user can see into a new project they own
+// POST /projects/:id/clone
+func (controller *Controller) Clone(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.IndentedJSON(401, gin.H{"error": "unauthorized"})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.IndentedJSON(400, gin.H{"error": "invalid project id"})
+		return
+	}
+
+	var req CloneProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.IndentedJSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	project, err := controller.service.Clone(c.Request.Context(), userUUID, id, &req)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid options") {
+			c.IndentedJSON(400, gin.H{"error": err.Error()})
+			return
+		}
+		if strings.Contains(err.Error(), "not found") {
+			c.IndentedJSON(404, gin.H{"error": err.Error()})
+			return
+		}
+		if strings.Contains(err.Error(), "unauthorized") {
+			c.IndentedJSON(403, gin.H{"error": err.Error()})
+			return
+		}
+		c.IndentedJSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.IndentedJSON(201, project)
+}
@@ -0,0 +1,67 @@
+package template
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateTemplateRequest struct {
+	// ProjectID is the project whose tasks the template is saved from
+	ProjectID       int64  `json:"project_id" binding:"required,min=1"`
+	Name            string `json:"name" binding:"required,min=1,max=255"`
+	Description     string `json:"description" binding:"omitempty,max=255"`
+	IncludeSubtasks bool   `json:"include_subtasks"`
+}
+
+type CloneProjectRequest struct {
+	// Name defaults to the source name with " (copy)" appended
+	Name            string `json:"name" binding:"omitempty,min=1,max=255"`
+	IncludeTasks    bool   `json:"include_tasks"`
+	IncludeSubtasks bool   `json:"include_subtasks"`
+	IncludeMembers  bool   `json:"include_members"`
+	// StartDate moves every due date by the time between the source
+	// project's creation and this date; without it due dates are copied as is
+	StartDate *time.Time `json:"start_date"`
+}
+
+type TemplateResponse struct {
+	ID          int64           `json:"id"`
+	OwnerID     uuid.UUID       `json:"owner_id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	TaskCount   int             `json:"task_count"`
+	Tasks       []*TemplateTask `json:"tasks,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+// ToTemplateResponse leaves the tasks out unless withTasks is set
+func ToTemplateResponse(template *ProjectTemplate, withTasks bool) *TemplateResponse {
+	var tasks []*TemplateTask
+	_ = json.Unmarshal([]byte(template.Tasks), &tasks)
+
+	res := &TemplateResponse{
+		ID:          template.ID,
+		OwnerID:     template.OwnerID,
+		Name:        template.Name,
+		Description: template.Description,
+		TaskCount:   len(tasks),
+		CreatedAt:   template.CreatedAt,
+	}
+	if withTasks {
+		res.Tasks = tasks
+		if res.Tasks == nil {
+			res.Tasks = []*TemplateTask{}
+		}
+	}
+	return res
+}
+
+func ToTemplateResponseList(templates []*ProjectTemplate) []*TemplateResponse {
+	responses := make([]*TemplateResponse, len(templates))
+	for i, template := range templates {
+		responses[i] = ToTemplateResponse(template, false)
+	}
+	return responses
+}
@@ -0,0 +1,39 @@
+package template
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProjectTemplate is a saved set of tasks and subtasks that new projects can
+// start from. Templates are private to the user who saved them.
+type ProjectTemplate struct {
+	ID          int64     `gorm:"primaryKey;autoIncrement"`
+	OwnerID     uuid.UUID `gorm:"type:uuid;not null;index"`
+	Name        string    `gorm:"type:varchar(255);not null"`
+	Description string    `gorm:"type:varchar(255)"`
+	// Tasks is the JSON encoded []TemplateTask
+	Tasks     string    `gorm:"type:jsonb;not null"`
+	CreatedAt time.Time `gorm:"type:timestamp;not null"`
+}
+
+func (ProjectTemplate) TableName() string {
+	return "project_template"
+}
+
+// TemplateTask keeps due dates as a number of days after the project start
+type TemplateTask struct {
+	Title       string            `json:"title"`
+	Description *string           `json:"description,omitempty"`
+	Priority    *string           `json:"priority,omitempty"`
+	DueInDays   *int              `json:"due_in_days,omitempty"`
+	Subtasks    []TemplateSubtask `json:"subtasks,omitempty"`
+}
+
+type TemplateSubtask struct {
+	Title       string  `json:"title"`
+	Description *string `json:"description,omitempty"`
+	Priority    *string `json:"priority,omitempty"`
+	DueInDays   *int    `json:"due_in_days,omitempty"`
+}
@@ -0,0 +1,153 @@
+package template
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"task-management/internal/project"
+	"task-management/internal/subtask"
+	"task-management/internal/task"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{db: db}
+}
+
+// plannedTask is a task to create in a new project together with its subtasks
+type plannedTask struct {
+	task     task.Task
+	subtasks []subtask.Subtask
+}
+
+func (r *Repository) Create(ctx context.Context, template *ProjectTemplate) error {
+	err := r.db.WithContext(ctx).Create(template).Error
+	if err != nil {
+		return fmt.Errorf("template - Create: %v", err)
+	}
+	return nil
+}
+
+func (r *Repository) FindByID(ctx context.Context, id int64) (*ProjectTemplate, error) {
+	var template ProjectTemplate
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&template).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("template not found")
+		}
+		return nil, fmt.Errorf("FindByID: %v", err)
+	}
+	return &template, nil
+}
+
+func (r *Repository) FindByOwnerID(ctx context.Context, ownerID uuid.UUID) ([]*ProjectTemplate, error) {
+	var templates []*ProjectTemplate
+	err := r.db.WithContext(ctx).Where("owner_id = ?", ownerID).Order("name").Find(&templates).Error
+	if err != nil {
+		return nil, fmt.Errorf("FindByOwnerID: %v", err)
+	}
+	return templates, nil
+}
+
+// Delete reports false when the user has no template with that id
+func (r *Repository) Delete(ctx context.Context, id int64, ownerID uuid.UUID) (bool, error) {
+	res := r.db.WithContext(ctx).Where("id = ? AND owner_id = ?", id, ownerID).Delete(&ProjectTemplate{})
+	if res.Error != nil {
+		return false, fmt.Errorf("template - Delete: %v", res.Error)
+	}
+	return res.RowsAffected > 0, nil
+}
+
+func (r *Repository) TasksOf(ctx context.Context, projectID int64) ([]*task.Task, error) {
+	var tasks []*task.Task
+	err := r.db.WithContext(ctx).Where("project_id = ?", projectID).Order("id").Find(&tasks).Error
+	if err != nil {
+		return nil, fmt.Errorf("TasksOf: %v", err)
+	}
+	return tasks, nil
+}
+
+func (r *Repository) SubtasksOf(ctx context.Context, taskIDs []int64) ([]subtask.Subtask, error) {
+	var subtasks []subtask.Subtask
+	if len(taskIDs) == 0 {
+		return subtasks, nil
+	}
+	err := r.db.WithContext(ctx).Where("task_id IN ?", taskIDs).Order("id").Find(&subtasks).Error
+	if err != nil {
+		return nil, fmt.Errorf("SubtasksOf: %v", err)
+	}
+	return subtasks, nil
+}
+
+func (r *Repository) MembersOf(ctx context.Context, projectID int64) ([]project.ProjectMember, error) {
+	var members []project.ProjectMember
+	err := r.db.WithContext(ctx).Where("project_id = ?", projectID).Order("id").Find(&members).Error
+	if err != nil {
+		return nil, fmt.Errorf("MembersOf: %v", err)
+	}
+	return members, nil
+}
+
+// CreateProject creates the project with its tasks, subtasks, assignees,
+// watchers and members in one transaction and records how it came to be in
+// its history. The owner and the assignees watch the tasks.
+func (r *Repository) CreateProject(ctx context.Context, p *project.Project, tasks []*plannedTask, members []project.ProjectMember,
+	eventType string, details map[string]interface{}) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(p).Error; err != nil {
+			return err
+		}
+
+		for _, planned := range tasks {
+			planned.task.ProjectID = p.ID
+			if err := tx.Create(&planned.task).Error; err != nil {
+				return err
+			}
+			watchers := []task.TaskWatcher{{TaskID: planned.task.ID, UserID: p.OwnerID, CreatedAt: planned.task.CreatedAt}}
+			for _, assignee := range planned.task.Assignees {
+				row := &task.TaskAssignee{TaskID: planned.task.ID, UserID: assignee, CreatedAt: planned.task.CreatedAt}
+				if err := tx.Create(row).Error; err != nil {
+					return err
+				}
+				if assignee != p.OwnerID {
+					watchers = append(watchers, task.TaskWatcher{TaskID: planned.task.ID, UserID: assignee, CreatedAt: planned.task.CreatedAt})
+				}
+			}
+			if err := tx.Create(&watchers).Error; err != nil {
+				return err
+			}
+			if len(planned.subtasks) == 0 {
+				continue
+			}
+			for i := range planned.subtasks {
+				planned.subtasks[i].TaskID = planned.task.ID
+			}
+			if err := tx.Create(&planned.subtasks).Error; err != nil {
+				return err
+			}
+		}
+
+		for i := range members {
+			members[i].ID = 0
+			members[i].ProjectID = p.ID
+		}
+		if len(members) > 0 {
+			if err := tx.Create(&members).Error; err != nil {
+				return err
+			}
+		}
+
+		return project.RecordEvent(tx, p.ID, p.OwnerID, eventType, details)
+	})
+	if err != nil {
+		return fmt.Errorf("CreateProject: %v", err)
+	}
+	return nil
+}
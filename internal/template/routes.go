@@ -0,0 +1,20 @@
+package template
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRoutes(group *gin.RouterGroup, controller *Controller,
+	authMw gin.HandlerFunc, scopeMw gin.HandlerFunc, rateLimitMw gin.HandlerFunc) {
+	templates := group.Group("")
+	templates.Use(authMw)
+	templates.Use(scopeMw)
+	templates.Use(rateLimitMw)
+	{
+		templates.GET("/templates", controller.List)
+		templates.POST("/templates", controller.Create)
+		templates.GET("/templates/:id", controller.GetByID)
+		templates.DELETE("/templates/:id", controller.Delete)
+		templates.POST("/projects/:id/clone", controller.Clone)
+	}
+}
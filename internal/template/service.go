@@ -0,0 +1,352 @@
+package template
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"task-management/internal/project"
+	"task-management/internal/subtask"
+	"task-management/internal/task"
+
+	"github.com/google/uuid"
+)
+
+type Service interface {
+	// Create saves the tasks of a project the user can see as a template
+	Create(ctx context.Context, userID uuid.UUID, dto *CreateTemplateRequest) (*TemplateResponse, error)
+	List(ctx context.Context, userID uuid.UUID) ([]*TemplateResponse, error)
+	GetByID(ctx context.Context, userID uuid.UUID, id int64) (*TemplateResponse, error)
+	Delete(ctx context.Context, userID uuid.UUID, id int64) error
+	// Instantiate implements project.TemplateInstantiator
+	Instantiate(ctx context.Context, userID uuid.UUID, templateID int64, p *project.Project, startDate *time.Time) error
+	Clone(ctx context.Context, userID uuid.UUID, projectID int64, dto *CloneProjectRequest) (*project.ProjectResponse, error)
+}
+
+type service struct {
+	repo        *Repository
+	projectRepo *project.Repository
+	taskRepo    *task.Repository
+}
+
+func NewService(repo *Repository, projectRepo *project.Repository, taskRepo *task.Repository) Service {
+	return &service{repo: repo, projectRepo: projectRepo, taskRepo: taskRepo}
+}
+
+// startOfDay is the date projects count their due dates from
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// daysAfter is the whole number of days from start to due, or nil without a due date
+func daysAfter(start time.Time, due *time.Time) *int {
+	if due == nil {
+		return nil
+	}
+	days := int(startOfDay(*due).Sub(startOfDay(start)).Hours() / 24)
+	return &days
+}
+
+// dueOn turns days after start back into a due date
+func dueOn(start time.Time, days *int) *time.Time {
+	if days == nil {
+		return nil
+	}
+	due := startOfDay(start).AddDate(0, 0, *days)
+	return &due
+}
+
+// shiftDue moves a due date by whole days
+func shiftDue(due *time.Time, days int) *time.Time {
+	if due == nil {
+		return nil
+	}
+	shifted := due.AddDate(0, 0, days)
+	return &shifted
+}
+
+func (s *service) Create(ctx context.Context, userID uuid.UUID, dto *CreateTemplateRequest) (*TemplateResponse, error) {
+	p, _, err := s.projectRepo.Authorize(ctx, dto.ProjectID, userID, project.ActionView)
+	if err != nil {
+		return nil, fmt.Errorf("Create: %v", err)
+	}
+
+	tasks, err := s.repo.TasksOf(ctx, p.ID)
+	if err != nil {
+		return nil, fmt.Errorf("Create: %v", err)
+	}
+
+	subtasksByTask := map[int64][]subtask.Subtask{}
+	if dto.IncludeSubtasks {
+		subtasksByTask, err = s.subtasksByTask(ctx, tasks)
+		if err != nil {
+			return nil, fmt.Errorf("Create: %v", err)
+		}
+	}
+
+	templateTasks := make([]*TemplateTask, len(tasks))
+	for i, t := range tasks {
+		templateTasks[i] = &TemplateTask{
+			Title:       t.Title,
+			Description: t.Description,
+			Priority:    t.Priority,
+			DueInDays:   daysAfter(p.CreatedAt, t.DueDate),
+		}
+		for _, st := range subtasksByTask[t.ID] {
+			templateTasks[i].Subtasks = append(templateTasks[i].Subtasks, TemplateSubtask{
+				Title:       st.Title,
+				Description: st.Description,
+				Priority:    st.Priority,
+				DueInDays:   daysAfter(p.CreatedAt, st.DueDate),
+			})
+		}
+	}
+
+	encoded, err := json.Marshal(templateTasks)
+	if err != nil {
+		return nil, fmt.Errorf("Create: %v", err)
+	}
+
+	template := &ProjectTemplate{
+		OwnerID:     userID,
+		Name:        dto.Name,
+		Description: dto.Description,
+		Tasks:       string(encoded),
+		CreatedAt:   time.Now(),
+	}
+	if err := s.repo.Create(ctx, template); err != nil {
+		return nil, fmt.Errorf("Create: %v", err)
+	}
+
+	return ToTemplateResponse(template, true), nil
+}
+
+func (s *service) subtasksByTask(ctx context.Context, tasks []*task.Task) (map[int64][]subtask.Subtask, error) {
+	taskIDs := make([]int64, len(tasks))
+	for i, t := range tasks {
+		taskIDs[i] = t.ID
+	}
+
+	subtasks, err := s.repo.SubtasksOf(ctx, taskIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	byTask := map[int64][]subtask.Subtask{}
+	for _, st := range subtasks {
+		byTask[st.TaskID] = append(byTask[st.TaskID], st)
+	}
+	return byTask, nil
+}
+
+func (s *service) List(ctx context.Context, userID uuid.UUID) ([]*TemplateResponse, error) {
+	templates, err := s.repo.FindByOwnerID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("List: %v", err)
+	}
+	return ToTemplateResponseList(templates), nil
+}
+
+// owned loads a template, hiding other people's templates as not found
+func (s *service) owned(ctx context.Context, userID uuid.UUID, id int64) (*ProjectTemplate, error) {
+	template, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if template.OwnerID != userID {
+		return nil, fmt.Errorf("template not found")
+	}
+	return template, nil
+}
+
+func (s *service) GetByID(ctx context.Context, userID uuid.UUID, id int64) (*TemplateResponse, error) {
+	template, err := s.owned(ctx, userID, id)
+	if err != nil {
+		return nil, fmt.Errorf("GetByID: %v", err)
+	}
+	return ToTemplateResponse(template, true), nil
+}
+
+func (s *service) Delete(ctx context.Context, userID uuid.UUID, id int64) error {
+	deleted, err := s.repo.Delete(ctx, id, userID)
+	if err != nil {
+		return fmt.Errorf("Delete: %v", err)
+	}
+	if !deleted {
+		return fmt.Errorf("template not found - Delete")
+	}
+	return nil
+}
+
+func (s *service) Instantiate(ctx context.Context, userID uuid.UUID, templateID int64, p *project.Project, startDate *time.Time) error {
+	template, err := s.owned(ctx, userID, templateID)
+	if err != nil {
+		return fmt.Errorf("Instantiate: %v", err)
+	}
+
+	var templateTasks []*TemplateTask
+	if err := json.Unmarshal([]byte(template.Tasks), &templateTasks); err != nil {
+		return fmt.Errorf("Instantiate: %v", err)
+	}
+
+	start := p.CreatedAt
+	if startDate != nil {
+		start = *startDate
+	}
+
+	now := time.Now()
+	planned := make([]*plannedTask, len(templateTasks))
+	for i, tt := range templateTasks {
+		planned[i] = &plannedTask{task: task.Task{
+			Title:       tt.Title,
+			Description: tt.Description,
+			Status:      task.StatusTodo,
+			Priority:    tt.Priority,
+			DueDate:     dueOn(start, tt.DueInDays),
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}}
+		for _, ts := range tt.Subtasks {
+			planned[i].subtasks = append(planned[i].subtasks, subtask.Subtask{
+				Title:       ts.Title,
+				Description: ts.Description,
+				Status:      subtask.StatusTodo,
+				Priority:    ts.Priority,
+				DueDate:     dueOn(start, ts.DueInDays),
+				CreatedAt:   now,
+				UpdatedAt:   now,
+			})
+		}
+	}
+
+	err = s.repo.CreateProject(ctx, p, planned, nil, project.EventCreatedFromTemplate, map[string]interface{}{
+		"template_id": template.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("Instantiate: %v", err)
+	}
+	return nil
+}
+
+func (s *service) Clone(ctx context.Context, userID uuid.UUID, projectID int64, dto *CloneProjectRequest) (*project.ProjectResponse, error) {
+	if dto.IncludeSubtasks && !dto.IncludeTasks {
+		return nil, fmt.Errorf("invalid options: include_subtasks requires include_tasks")
+	}
+
+	source, _, err := s.projectRepo.Authorize(ctx, projectID, userID, project.ActionView)
+	if err != nil {
+		return nil, fmt.Errorf("Clone: %v", err)
+	}
+
+	name := dto.Name
+	if name == "" {
+		name = source.Name + " (copy)"
+		if len(name) > 255 {
+			name = source.Name
+		}
+	}
+
+	now := time.Now()
+	clone := &project.Project{
+		OwnerID:     userID,
+		Name:        name,
+		Description: source.Description,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	// the caller owns the clone; the source owner, if someone else, stays on as admin
+	var members []project.ProjectMember
+	inClone := map[uuid.UUID]bool{userID: true}
+	if dto.IncludeMembers {
+		sourceMembers, err := s.repo.MembersOf(ctx, source.ID)
+		if err != nil {
+			return nil, fmt.Errorf("Clone: %v", err)
+		}
+		if source.OwnerID != userID {
+			sourceMembers = append(sourceMembers, project.ProjectMember{UserID: source.OwnerID, Role: project.RoleAdmin})
+		}
+		for _, m := range sourceMembers {
+			if inClone[m.UserID] {
+				continue
+			}
+			inClone[m.UserID] = true
+			members = append(members, project.ProjectMember{UserID: m.UserID, Role: m.Role})
+		}
+	}
+
+	shift := 0
+	if dto.StartDate != nil {
+		shift = *daysAfter(source.CreatedAt, dto.StartDate)
+	}
+
+	var planned []*plannedTask
+	if dto.IncludeTasks {
+		tasks, err := s.repo.TasksOf(ctx, source.ID)
+		if err != nil {
+			return nil, fmt.Errorf("Clone: %v", err)
+		}
+		if err := s.taskRepo.LoadDetails(ctx, tasks); err != nil {
+			return nil, fmt.Errorf("Clone: %v", err)
+		}
+
+		subtasksByTask := map[int64][]subtask.Subtask{}
+		if dto.IncludeSubtasks {
+			subtasksByTask, err = s.subtasksByTask(ctx, tasks)
+			if err != nil {
+				return nil, fmt.Errorf("Clone: %v", err)
+			}
+		}
+
+		// clones start with all work open again
+		for _, t := range tasks {
+			pt := &plannedTask{task: task.Task{
+				Title:       t.Title,
+				Description: t.Description,
+				Status:      task.StatusTodo,
+				Priority:    t.Priority,
+				DueDate:     shiftDue(t.DueDate, shift),
+				CreatedAt:   now,
+				UpdatedAt:   now,
+			}}
+			for _, assignee := range t.Assignees {
+				if inClone[assignee] {
+					pt.task.Assignees = append(pt.task.Assignees, assignee)
+				}
+			}
+			for _, st := range subtasksByTask[t.ID] {
+				assignee := st.AssignedTo
+				if assignee != nil && !inClone[*assignee] {
+					assignee = nil
+				}
+				pt.subtasks = append(pt.subtasks, subtask.Subtask{
+					AssignedTo:  assignee,
+					Title:       st.Title,
+					Description: st.Description,
+					Status:      subtask.StatusTodo,
+					Priority:    st.Priority,
+					DueDate:     shiftDue(st.DueDate, shift),
+					CreatedAt:   now,
+					UpdatedAt:   now,
+				})
+			}
+			planned = append(planned, pt)
+		}
+	}
+
+	err = s.repo.CreateProject(ctx, clone, planned, members, project.EventCloned, map[string]interface{}{
+		"source_project_id": source.ID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Clone: %v", err)
+	}
+
+	return project.ToProjectSummaryResponse(&project.ProjectSummary{
+		Project:        *clone,
+		Role:           project.RoleOwner,
+		MemberCount:    int64(len(members)) + 1,
+		LastActivityAt: now,
+	}), nil
+}
@@ -0,0 +1,169 @@
+// Package testutil holds fakes shared by the tests of several packages
+package testutil
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// NewRedis returns a client whose commands are answered from memory instead
+// of a server. It understands the string commands the services use (GET, SET
+// with NX/EX/PX, SETNX, GETDEL, DEL, INCR, EXPIRE and TTL). Expirations are
+// recorded for TTL but never run out.
+func NewRedis() *redis.Client {
+	client := redis.NewClient(&redis.Options{Addr: "fake-redis:6379"})
+	client.AddHook(&memoryRedis{data: map[string]string{}, ttl: map[string]time.Duration{}})
+	return client
+}
+
+type memoryRedis struct {
+	mu   sync.Mutex
+	data map[string]string
+	ttl  map[string]time.Duration
+}
+
+func (m *memoryRedis) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return nil, fmt.Errorf("testutil: the in-memory redis has no connection")
+	}
+}
+
+func (m *memoryRedis) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		for _, cmd := range cmds {
+			if err := m.process(cmd); err != nil && err != redis.Nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func (m *memoryRedis) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		return m.process(cmd)
+	}
+}
+
+func (m *memoryRedis) process(cmd redis.Cmder) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	args := cmd.Args()
+	switch cmd.Name() {
+	case "set", "setnx":
+		key := fmt.Sprint(args[1])
+		onlyNew := cmd.Name() == "setnx"
+		for _, arg := range args[3:] {
+			if s, ok := arg.(string); ok && strings.EqualFold(s, "nx") {
+				onlyNew = true
+			}
+		}
+		var ttl time.Duration
+		for i := 3; i+1 < len(args); i++ {
+			switch strings.ToLower(fmt.Sprint(args[i])) {
+			case "ex":
+				ttl = time.Duration(toInt(args[i+1])) * time.Second
+			case "px":
+				ttl = time.Duration(toInt(args[i+1])) * time.Millisecond
+			}
+		}
+		_, exists := m.data[key]
+		stored := !(onlyNew && exists)
+		if stored {
+			m.data[key] = toString(args[2])
+			delete(m.ttl, key)
+			if ttl > 0 {
+				m.ttl[key] = ttl
+			}
+		}
+
+		switch c := cmd.(type) {
+		case *redis.BoolCmd:
+			c.SetVal(stored)
+		case *redis.StatusCmd:
+			if !stored {
+				c.SetErr(redis.Nil)
+				return redis.Nil
+			}
+			c.SetVal("OK")
+		}
+		return nil
+	case "get", "getdel":
+		key := fmt.Sprint(args[1])
+		value, ok := m.data[key]
+		c := cmd.(*redis.StringCmd)
+		if !ok {
+			c.SetErr(redis.Nil)
+			return redis.Nil
+		}
+		if cmd.Name() == "getdel" {
+			delete(m.data, key)
+		}
+		c.SetVal(value)
+		return nil
+	case "del":
+		var removed int64
+		for _, arg := range args[1:] {
+			key := fmt.Sprint(arg)
+			if _, ok := m.data[key]; ok {
+				delete(m.data, key)
+				delete(m.ttl, key)
+				removed++
+			}
+		}
+		cmd.(*redis.IntCmd).SetVal(removed)
+		return nil
+	case "incr":
+		key := fmt.Sprint(args[1])
+		value := toInt(m.data[key]) + 1
+		m.data[key] = strconv.FormatInt(value, 10)
+		cmd.(*redis.IntCmd).SetVal(value)
+		return nil
+	case "expire":
+		key := fmt.Sprint(args[1])
+		_, exists := m.data[key]
+		if exists {
+			m.ttl[key] = time.Duration(toInt(args[2])) * time.Second
+		}
+		cmd.(*redis.BoolCmd).SetVal(exists)
+		return nil
+	case "ttl":
+		key := fmt.Sprint(args[1])
+		c := cmd.(*redis.DurationCmd)
+		if _, exists := m.data[key]; !exists {
+			c.SetVal(-2)
+		} else if ttl, ok := m.ttl[key]; ok {
+			c.SetVal(ttl)
+		} else {
+			c.SetVal(-1)
+		}
+		return nil
+	}
+
+	err := fmt.Errorf("testutil: unsupported redis command %q", cmd.Name())
+	cmd.SetErr(err)
+	return err
+}
+
+func toString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	}
+	return fmt.Sprint(value)
+}
+
+func toInt(value interface{}) int64 {
+	n, _ := strconv.ParseInt(toString(value), 10, 64)
+	return n
+}
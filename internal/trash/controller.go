@@ -0,0 +1,81 @@
+package trash
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type Controller struct {
+	service Service
+}
+
+func NewController(service Service) *Controller {
+	return &Controller{service: service}
+}
+
+func (controller *Controller) List(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.IndentedJSON(401, gin.H{"error": "unauthorized"})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+
+	items, err := controller.service.List(c.Request.Context(), userUUID)
+	if err != nil {
+		c.IndentedJSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.IndentedJSON(200, items)
+}
+
+func (controller *Controller) RestoreProject(c *gin.Context) {
+	controller.restore(c, "project", controller.service.RestoreProject)
+}
+
+func (controller *Controller) RestoreTask(c *gin.Context) {
+	controller.restore(c, "task", controller.service.RestoreTask)
+}
+
+func (controller *Controller) RestoreSubtask(c *gin.Context) {
+	controller.restore(c, "subtask", controller.service.RestoreSubtask)
+}
+
+func (controller *Controller) restore(c *gin.Context, kind string, restore func(context.Context, uuid.UUID, int64) error) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.IndentedJSON(401, gin.H{"error": "unauthorized"})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.IndentedJSON(400, gin.H{"error": "invalid " + kind + " id"})
+		return
+	}
+
+	if err := restore(c.Request.Context(), userUUID, id); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.IndentedJSON(404, gin.H{"error": err.Error()})
+			return
+		}
+		if strings.Contains(err.Error(), "unauthorized") {
+			c.IndentedJSON(403, gin.H{"error": err.Error()})
+			return
+		}
+		if strings.Contains(err.Error(), "trash") || strings.Contains(err.Error(), "archived") {
+			c.IndentedJSON(409, gin.H{"error": err.Error()})
+			return
+		}
+		c.IndentedJSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.IndentedJSON(200, gin.H{"message": kind + " restored"})
+}
@@ -0,0 +1,38 @@
+package trash
+
+import (
+	"time"
+)
+
+type ItemResponse struct {
+	Type        string    `json:"type"`
+	ID          int64     `json:"id"`
+	Name        string    `json:"name"`
+	ProjectID   int64     `json:"project_id"`
+	ProjectName string    `json:"project_name"`
+	TaskID      *int64    `json:"task_id,omitempty"`
+	DeletedAt   time.Time `json:"deleted_at"`
+	// PurgeAt is when the item is deleted for good
+	PurgeAt time.Time `json:"purge_at"`
+}
+
+func ToItemResponse(item *Item, retention time.Duration) *ItemResponse {
+	return &ItemResponse{
+		Type:        item.Type,
+		ID:          item.ID,
+		Name:        item.Name,
+		ProjectID:   item.ProjectID,
+		ProjectName: item.ProjectName,
+		TaskID:      item.TaskID,
+		DeletedAt:   item.DeletedAt,
+		PurgeAt:     item.DeletedAt.Add(retention),
+	}
+}
+
+func ToItemResponseList(items []*Item, retention time.Duration) []*ItemResponse {
+	responses := make([]*ItemResponse, len(items))
+	for i, item := range items {
+		responses[i] = ToItemResponse(item, retention)
+	}
+	return responses
+}
@@ -0,0 +1,25 @@
+package trash
+
+import (
+	"time"
+)
+
+// Kinds of items in the trash
+const (
+	TypeProject = "project"
+	TypeTask    = "task"
+	TypeSubtask = "subtask"
+)
+
+// Item is one trashed project, task or subtask. Tasks and subtasks that went
+// to the trash with their project or task are not listed on their own; they
+// come back when their parent is restored.
+type Item struct {
+	Type        string
+	ID          int64
+	Name        string
+	ProjectID   int64
+	ProjectName string
+	TaskID      *int64
+	DeletedAt   time.Time
+}
@@ -0,0 +1,29 @@
+package trash
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// RunPurger purges expired trash right away and then every interval until
+// ctx is cancelled. Run it in its own goroutine.
+func RunPurger(ctx context.Context, service Service, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := service.Purge(ctx)
+		if err != nil {
+			log.Printf("failed to purge trash - RunPurger: %v", err)
+		} else if purged > 0 {
+			log.Printf("purged %d items from the trash", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package trash
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"task-management/internal/project"
+	"task-management/internal/subtask"
+	"task-management/internal/task"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Repository works on trashed rows, which the feature repositories never see
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{db: db}
+}
+
+// accessible limits a query joined with project to projects where userID
+// is the owner or a member with one of roles
+func accessible(query *gorm.DB, userID uuid.UUID, roles []string) *gorm.DB {
+	return query.
+		Joins("LEFT JOIN project_member membership ON membership.project_id = project.id AND membership.user_id = ?", userID).
+		Where("project.owner_id = ? OR membership.role IN ?", userID, roles)
+}
+
+func (r *Repository) ListProjects(ctx context.Context, userID uuid.UUID) ([]*Item, error) {
+	var items []*Item
+	err := r.db.WithContext(ctx).Table("project").
+		Select("'project' AS type, project.id, project.name, project.id AS project_id, project.name AS project_name, project.deleted_at").
+		Where("project.owner_id = ? AND project.deleted_at IS NOT NULL", userID).
+		Scan(&items).Error
+	if err != nil {
+		return nil, fmt.Errorf("ListProjects: %v", err)
+	}
+	return items, nil
+}
+
+// ListTasks returns tasks trashed on their own in live projects where the
+// user has one of roles
+func (r *Repository) ListTasks(ctx context.Context, userID uuid.UUID, roles []string) ([]*Item, error) {
+	var items []*Item
+	query := r.db.WithContext(ctx).Table("task").
+		Select("'task' AS type, task.id, task.title AS name, project.id AS project_id, project.name AS project_name, task.deleted_at").
+		Joins("JOIN project ON project.id = task.project_id").
+		Where("task.deleted_at IS NOT NULL AND project.deleted_at IS NULL")
+	err := accessible(query, userID, roles).Scan(&items).Error
+	if err != nil {
+		return nil, fmt.Errorf("ListTasks: %v", err)
+	}
+	return items, nil
+}
+
+// ListSubtasks returns subtasks trashed on their own under live tasks in
+// projects where the user has one of roles
+func (r *Repository) ListSubtasks(ctx context.Context, userID uuid.UUID, roles []string) ([]*Item, error) {
+	var items []*Item
+	query := r.db.WithContext(ctx).Table("subtask").
+		Select(`'subtask' AS type, subtask.id, subtask.title AS name, project.id AS project_id,
+			project.name AS project_name, task.id AS task_id, subtask.deleted_at`).
+		Joins("JOIN task ON task.id = subtask.task_id").
+		Joins("JOIN project ON project.id = task.project_id").
+		Where("subtask.deleted_at IS NOT NULL AND task.deleted_at IS NULL AND project.deleted_at IS NULL")
+	err := accessible(query, userID, roles).Scan(&items).Error
+	if err != nil {
+		return nil, fmt.Errorf("ListSubtasks: %v", err)
+	}
+	return items, nil
+}
+
+func (r *Repository) FindProject(ctx context.Context, id int64) (*project.Project, error) {
+	var p project.Project
+	err := r.db.WithContext(ctx).Unscoped().Where("id = ?", id).First(&p).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("project not found")
+		}
+		return nil, fmt.Errorf("FindProject: %v", err)
+	}
+	return &p, nil
+}
+
+func (r *Repository) FindTask(ctx context.Context, id int64) (*task.Task, error) {
+	var t task.Task
+	err := r.db.WithContext(ctx).Unscoped().Where("id = ?", id).First(&t).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("task not found")
+		}
+		return nil, fmt.Errorf("FindTask: %v", err)
+	}
+	return &t, nil
+}
+
+func (r *Repository) FindSubtask(ctx context.Context, id int64) (*subtask.Subtask, error) {
+	var s subtask.Subtask
+	err := r.db.WithContext(ctx).Unscoped().Where("id = ?", id).First(&s).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("subtask not found")
+		}
+		return nil, fmt.Errorf("FindSubtask: %v", err)
+	}
+	return &s, nil
+}
+
+// RestoreProject brings back the project and whatever was trashed with it,
+// recognised by the shared deleted_at
+func (r *Repository) RestoreProject(ctx context.Context, p *project.Project, userID uuid.UUID) error {
+	deletedAt := p.DeletedAt.Time
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`UPDATE subtask SET deleted_at = NULL WHERE deleted_at = ?
+			AND task_id IN (SELECT id FROM task WHERE project_id = ? AND deleted_at = ?)`, deletedAt, p.ID, deletedAt).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Exec("UPDATE task SET deleted_at = NULL WHERE project_id = ? AND deleted_at = ?", p.ID, deletedAt).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Exec("UPDATE project SET deleted_at = NULL WHERE id = ?", p.ID).Error
+		if err != nil {
+			return err
+		}
+		return project.RecordEvent(tx, p.ID, userID, project.EventRestored, nil)
+	})
+	if err != nil {
+		return fmt.Errorf("RestoreProject: %v", err)
+	}
+	return nil
+}
+
+func (r *Repository) RestoreTask(ctx context.Context, t *task.Task) error {
+	deletedAt := t.DeletedAt.Time
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec("UPDATE subtask SET deleted_at = NULL WHERE task_id = ? AND deleted_at = ?", t.ID, deletedAt).Error
+		if err != nil {
+			return err
+		}
+		return tx.Exec("UPDATE task SET deleted_at = NULL WHERE id = ?", t.ID).Error
+	})
+	if err != nil {
+		return fmt.Errorf("RestoreTask: %v", err)
+	}
+	return nil
+}
+
+func (r *Repository) RestoreSubtask(ctx context.Context, id int64) error {
+	err := r.db.WithContext(ctx).Exec("UPDATE subtask SET deleted_at = NULL WHERE id = ?", id).Error
+	if err != nil {
+		return fmt.Errorf("RestoreSubtask: %v", err)
+	}
+	return nil
+}
+
+// Purge permanently deletes everything trashed before cutoff. Children of a
+// purged project or task go with it through ON DELETE CASCADE.
+func (r *Repository) Purge(ctx context.Context, cutoff time.Time) (int64, error) {
+	var purged int64
+	for _, table := range []string{"project", "task", "subtask"} {
+		res := r.db.WithContext(ctx).Exec("DELETE FROM "+table+" WHERE deleted_at < ?", cutoff)
+		if res.Error != nil {
+			return purged, fmt.Errorf("Purge: %v", res.Error)
+		}
+		purged += res.RowsAffected
+	}
+	return purged, nil
+}
@@ -0,0 +1,19 @@
+package trash
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRoutes(group *gin.RouterGroup, controller *Controller,
+	authMw gin.HandlerFunc, scopeMw gin.HandlerFunc, rateLimitMw gin.HandlerFunc) {
+	trash := group.Group("/trash")
+	trash.Use(authMw)
+	trash.Use(scopeMw)
+	trash.Use(rateLimitMw)
+	{
+		trash.GET("", controller.List)
+		trash.POST("/projects/:id/restore", controller.RestoreProject)
+		trash.POST("/tasks/:id/restore", controller.RestoreTask)
+		trash.POST("/subtasks/:id/restore", controller.RestoreSubtask)
+	}
+}
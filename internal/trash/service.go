@@ -0,0 +1,144 @@
+package trash
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"task-management/internal/config"
+	"task-management/internal/project"
+
+	"github.com/google/uuid"
+)
+
+type Service interface {
+	// List returns what the user may restore, most recently deleted first
+	List(ctx context.Context, userID uuid.UUID) ([]*ItemResponse, error)
+	RestoreProject(ctx context.Context, userID uuid.UUID, id int64) error
+	RestoreTask(ctx context.Context, userID uuid.UUID, id int64) error
+	RestoreSubtask(ctx context.Context, userID uuid.UUID, id int64) error
+	// Purge deletes items that have been in the trash longer than the retention
+	Purge(ctx context.Context) (int64, error)
+}
+
+type service struct {
+	repo        *Repository
+	projectRepo *project.Repository
+	retention   time.Duration
+}
+
+func NewService(config *config.Config, repo *Repository, projectRepo *project.Repository) Service {
+	return &service{
+		repo:        repo,
+		projectRepo: projectRepo,
+		retention:   time.Duration(config.Trash.RetentionDays) * 24 * time.Hour,
+	}
+}
+
+func (s *service) List(ctx context.Context, userID uuid.UUID) ([]*ItemResponse, error) {
+	projects, err := s.repo.ListProjects(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("List: %v", err)
+	}
+
+	// restoring needs the same role as deleting
+	tasks, err := s.repo.ListTasks(ctx, userID, project.RolesAllowed(project.ActionDeleteTask))
+	if err != nil {
+		return nil, fmt.Errorf("List: %v", err)
+	}
+
+	subtasks, err := s.repo.ListSubtasks(ctx, userID, project.RolesAllowed(project.ActionDeleteSubtask))
+	if err != nil {
+		return nil, fmt.Errorf("List: %v", err)
+	}
+
+	items := append(append(projects, tasks...), subtasks...)
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].DeletedAt.After(items[j].DeletedAt)
+	})
+
+	return ToItemResponseList(items, s.retention), nil
+}
+
+func (s *service) RestoreProject(ctx context.Context, userID uuid.UUID, id int64) error {
+	p, err := s.repo.FindProject(ctx, id)
+	if err != nil {
+		return fmt.Errorf("RestoreProject: %v", err)
+	}
+	if !p.DeletedAt.Valid {
+		return fmt.Errorf("project is not in the trash")
+	}
+
+	role, err := s.projectRepo.RoleOf(ctx, p, userID)
+	if err != nil {
+		return fmt.Errorf("RestoreProject: %v", err)
+	}
+	if role == "" {
+		return fmt.Errorf("project not found")
+	}
+	if !project.Can(role, project.ActionDeleteProject) {
+		return fmt.Errorf("unauthorized: %s role cannot restore the project", role)
+	}
+
+	return s.repo.RestoreProject(ctx, p, userID)
+}
+
+// liveProject authorizes action in the project of a trashed task or subtask,
+// which has to be restored first if it is in the trash itself
+func (s *service) liveProject(ctx context.Context, userID uuid.UUID, projectID int64, action project.Action) error {
+	p, err := s.repo.FindProject(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	if p.DeletedAt.Valid {
+		return fmt.Errorf("the project is in the trash, restore it first")
+	}
+
+	_, _, err = s.projectRepo.Authorize(ctx, projectID, userID, action)
+	return err
+}
+
+func (s *service) RestoreTask(ctx context.Context, userID uuid.UUID, id int64) error {
+	t, err := s.repo.FindTask(ctx, id)
+	if err != nil {
+		return fmt.Errorf("RestoreTask: %v", err)
+	}
+	if !t.DeletedAt.Valid {
+		return fmt.Errorf("task is not in the trash")
+	}
+
+	if err := s.liveProject(ctx, userID, t.ProjectID, project.ActionDeleteTask); err != nil {
+		return fmt.Errorf("RestoreTask: %v", err)
+	}
+
+	return s.repo.RestoreTask(ctx, t)
+}
+
+func (s *service) RestoreSubtask(ctx context.Context, userID uuid.UUID, id int64) error {
+	st, err := s.repo.FindSubtask(ctx, id)
+	if err != nil {
+		return fmt.Errorf("RestoreSubtask: %v", err)
+	}
+	if !st.DeletedAt.Valid {
+		return fmt.Errorf("subtask is not in the trash")
+	}
+
+	t, err := s.repo.FindTask(ctx, st.TaskID)
+	if err != nil {
+		return fmt.Errorf("RestoreSubtask: %v", err)
+	}
+	if t.DeletedAt.Valid {
+		return fmt.Errorf("the task is in the trash, restore it first")
+	}
+
+	if err := s.liveProject(ctx, userID, t.ProjectID, project.ActionDeleteSubtask); err != nil {
+		return fmt.Errorf("RestoreSubtask: %v", err)
+	}
+
+	return s.repo.RestoreSubtask(ctx, id)
+}
+
+func (s *service) Purge(ctx context.Context) (int64, error) {
+	return s.repo.Purge(ctx, time.Now().Add(-s.retention))
+}
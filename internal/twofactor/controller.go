@@ -0,0 +1,147 @@
+package twofactor
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type Controller struct {
+	service Service
+}
+
+func NewController(service Service) *Controller {
+	return &Controller{service: service}
+}
+
+func (controller *Controller) Status(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.IndentedJSON(401, gin.H{"error": "unauthorized"})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+
+	status, err := controller.service.Status(c.Request.Context(), userUUID)
+	if err != nil {
+		c.IndentedJSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.IndentedJSON(200, status)
+}
+
+func (controller *Controller) Setup(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.IndentedJSON(401, gin.H{"error": "unauthorized"})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+
+	res, err := controller.service.Setup(c.Request.Context(), userUUID)
+	if err != nil {
+		if err.Error() == "two-factor already enabled" {
+			c.IndentedJSON(409, gin.H{"error": err.Error()})
+			return
+		}
+		c.IndentedJSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.IndentedJSON(200, res)
+}
+
+func (controller *Controller) Enable(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.IndentedJSON(401, gin.H{"error": "unauthorized"})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+
+	var dto CodeRequest
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.IndentedJSON(400, gin.H{"error": "invalid request body: " + err.Error()})
+		return
+	}
+
+	res, err := controller.service.Enable(c.Request.Context(), userUUID, &dto)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid two-factor code") {
+			c.IndentedJSON(400, gin.H{"error": err.Error()})
+			return
+		}
+		if err.Error() == "two-factor already enabled" || err.Error() == "two-factor setup not started" {
+			c.IndentedJSON(409, gin.H{"error": err.Error()})
+			return
+		}
+		c.IndentedJSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.IndentedJSON(200, res)
+}
+
+func (controller *Controller) Disable(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.IndentedJSON(401, gin.H{"error": "unauthorized"})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+
+	var dto DisableRequest
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.IndentedJSON(400, gin.H{"error": "invalid request body: " + err.Error()})
+		return
+	}
+
+	err := controller.service.Disable(c.Request.Context(), userUUID, &dto)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid two-factor code") || strings.Contains(err.Error(), "password doesnt match") {
+			c.IndentedJSON(400, gin.H{"error": err.Error()})
+			return
+		}
+		if err.Error() == "two-factor not enabled" {
+			c.IndentedJSON(409, gin.H{"error": err.Error()})
+			return
+		}
+		c.IndentedJSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.IndentedJSON(200, gin.H{"message": "two-factor authentication disabled"})
+}
+
+func (controller *Controller) RegenerateRecoveryCodes(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.IndentedJSON(401, gin.H{"error": "unauthorized"})
+		return
+	}
+	userUUID := userID.(uuid.UUID)
+
+	var dto CodeRequest
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.IndentedJSON(400, gin.H{"error": "invalid request body: " + err.Error()})
+		return
+	}
+
+	res, err := controller.service.RegenerateRecoveryCodes(c.Request.Context(), userUUID, &dto)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid two-factor code") {
+			c.IndentedJSON(400, gin.H{"error": err.Error()})
+			return
+		}
+		if strings.Contains(err.Error(), "two-factor not enabled") {
+			c.IndentedJSON(409, gin.H{"error": err.Error()})
+			return
+		}
+		c.IndentedJSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.IndentedJSON(200, res)
+}
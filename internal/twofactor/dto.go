@@ -0,0 +1,24 @@
+package twofactor
+
+type CodeRequest struct {
+	Code string `json:"code" binding:"required,min=6,max=32"`
+}
+
+type DisableRequest struct {
+	Password string `json:"password" binding:"required,min=1,max=255"`
+	Code     string `json:"code" binding:"required,min=6,max=32"`
+}
+
+type StatusResponse struct {
+	Enabled                bool  `json:"enabled"`
+	RemainingRecoveryCodes int64 `json:"remaining_recovery_codes"`
+}
+
+type SetupResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
@@ -0,0 +1,22 @@
+package twofactor
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// number of single-use recovery codes handed out on enrollment
+const recoveryCodeCount = 10
+
+type RecoveryCode struct {
+	ID        int64      `gorm:"primaryKey;autoIncrement"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index"`
+	CodeHash  string     `gorm:"type:varchar(64);not null"`
+	UsedAt    *time.Time `gorm:"type:timestamp"`
+	CreatedAt time.Time  `gorm:"type:timestamp;not null"`
+}
+
+func (RecoveryCode) TableName() string {
+	return "recovery_code"
+}
@@ -0,0 +1,56 @@
+package twofactor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{db: db}
+}
+
+// ReplaceRecoveryCodes drops every previous code of the user and stores the new set
+func (r *Repository) ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codes []*RecoveryCode) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+		return tx.Create(&codes).Error
+	})
+	if err != nil {
+		return fmt.Errorf("ReplaceRecoveryCodes: %v", err)
+	}
+	return nil
+}
+
+// UseRecoveryCode consumes a matching unused code and reports whether one was found
+func (r *Repository) UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	if res.Error != nil {
+		return false, fmt.Errorf("UseRecoveryCode: %v", res.Error)
+	}
+	return res.RowsAffected > 0, nil
+}
+
+func (r *Repository) CountUnused(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&RecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("CountUnused: %v", err)
+	}
+	return count, nil
+}
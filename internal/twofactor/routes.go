@@ -0,0 +1,20 @@
+package twofactor
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRoutes(group *gin.RouterGroup, controller *Controller,
+	authMw gin.HandlerFunc, scopeMw gin.HandlerFunc, rateLimitMw gin.HandlerFunc) {
+	twoFactor := group.Group("/users/me/2fa")
+	twoFactor.Use(authMw)
+	twoFactor.Use(scopeMw)
+	twoFactor.Use(rateLimitMw)
+	{
+		twoFactor.GET("", controller.Status)
+		twoFactor.POST("/setup", controller.Setup)
+		twoFactor.POST("/enable", controller.Enable)
+		twoFactor.POST("/disable", controller.Disable)
+		twoFactor.POST("/recovery-codes", controller.RegenerateRecoveryCodes)
+	}
+}
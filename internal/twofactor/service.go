@@ -0,0 +1,231 @@
+package twofactor
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"fmt"
+	"strings"
+	"time"
+
+	"task-management/internal/config"
+	"task-management/internal/password"
+	"task-management/internal/user"
+	"task-management/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+type Service interface {
+	Status(ctx context.Context, userID uuid.UUID) (*StatusResponse, error)
+	Setup(ctx context.Context, userID uuid.UUID) (*SetupResponse, error)
+	Enable(ctx context.Context, userID uuid.UUID, dto *CodeRequest) (*RecoveryCodesResponse, error)
+	Disable(ctx context.Context, userID uuid.UUID, dto *DisableRequest) error
+	RegenerateRecoveryCodes(ctx context.Context, userID uuid.UUID, dto *CodeRequest) (*RecoveryCodesResponse, error)
+	// Verify accepts either a current TOTP code or an unused recovery code
+	Verify(ctx context.Context, userID uuid.UUID, code string) error
+}
+
+type service struct {
+	config   *config.Config
+	repo     *Repository
+	userRepo *user.Repository
+	hasher   password.Hasher
+	rdb      *redis.Client
+}
+
+func NewService(config *config.Config, repo *Repository, userRepo *user.Repository, hasher password.Hasher, rdb *redis.Client) Service {
+	return &service{config: config, repo: repo, userRepo: userRepo, hasher: hasher, rdb: rdb}
+}
+
+func (s *service) Status(ctx context.Context, userID uuid.UUID) (*StatusResponse, error) {
+	userInfo, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("Status: %v", err)
+	}
+
+	remaining, err := s.repo.CountUnused(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("Status: %v", err)
+	}
+
+	return &StatusResponse{
+		Enabled:                userInfo.TOTPEnabledAt != nil,
+		RemainingRecoveryCodes: remaining,
+	}, nil
+}
+
+func (s *service) Setup(ctx context.Context, userID uuid.UUID) (*SetupResponse, error) {
+	userInfo, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("Setup: %v", err)
+	}
+
+	if userInfo.TOTPEnabledAt != nil {
+		return nil, fmt.Errorf("two-factor already enabled")
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, fmt.Errorf("Setup: %v", err)
+	}
+
+	if err := s.userRepo.SetTOTPSecret(ctx, userID, secret); err != nil {
+		return nil, fmt.Errorf("Setup: %v", err)
+	}
+
+	return &SetupResponse{
+		Secret:          secret,
+		ProvisioningURI: utils.TOTPProvisioningURI(s.config.Auth.TOTPIssuer, userInfo.Email, secret),
+	}, nil
+}
+
+func (s *service) Enable(ctx context.Context, userID uuid.UUID, dto *CodeRequest) (*RecoveryCodesResponse, error) {
+	userInfo, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("Enable: %v", err)
+	}
+
+	if userInfo.TOTPEnabledAt != nil {
+		return nil, fmt.Errorf("two-factor already enabled")
+	}
+	if userInfo.TOTPSecret == nil {
+		return nil, fmt.Errorf("two-factor setup not started")
+	}
+
+	if err := s.verifyTOTP(ctx, userInfo, dto.Code); err != nil {
+		return nil, fmt.Errorf("Enable: %v", err)
+	}
+
+	if err := s.userRepo.EnableTOTP(ctx, userID); err != nil {
+		return nil, fmt.Errorf("Enable: %v", err)
+	}
+
+	codes, err := s.issueRecoveryCodes(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("Enable: %v", err)
+	}
+
+	return &RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+func (s *service) Disable(ctx context.Context, userID uuid.UUID, dto *DisableRequest) error {
+	userInfo, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("Disable: %v", err)
+	}
+
+	if userInfo.TOTPEnabledAt == nil {
+		return fmt.Errorf("two-factor not enabled")
+	}
+
+	if !s.hasher.Verify(dto.Password, userInfo.PasswordHash) {
+		return fmt.Errorf("password doesnt match - Disable")
+	}
+
+	if err := s.Verify(ctx, userID, dto.Code); err != nil {
+		return fmt.Errorf("Disable: %v", err)
+	}
+
+	if err := s.userRepo.DisableTOTP(ctx, userID); err != nil {
+		return fmt.Errorf("Disable: %v", err)
+	}
+
+	if err := s.repo.ReplaceRecoveryCodes(ctx, userID, nil); err != nil {
+		return fmt.Errorf("Disable: %v", err)
+	}
+
+	return nil
+}
+
+func (s *service) RegenerateRecoveryCodes(ctx context.Context, userID uuid.UUID, dto *CodeRequest) (*RecoveryCodesResponse, error) {
+	if err := s.Verify(ctx, userID, dto.Code); err != nil {
+		return nil, fmt.Errorf("RegenerateRecoveryCodes: %v", err)
+	}
+
+	codes, err := s.issueRecoveryCodes(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("RegenerateRecoveryCodes: %v", err)
+	}
+
+	return &RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+func (s *service) Verify(ctx context.Context, userID uuid.UUID, code string) error {
+	userInfo, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("Verify: %v", err)
+	}
+
+	if userInfo.TOTPEnabledAt == nil || userInfo.TOTPSecret == nil {
+		return fmt.Errorf("two-factor not enabled")
+	}
+
+	code = strings.TrimSpace(code)
+	if len(code) == 6 {
+		return s.verifyTOTP(ctx, userInfo, code)
+	}
+
+	used, err := s.repo.UseRecoveryCode(ctx, userID, utils.HashToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		return fmt.Errorf("Verify: %v", err)
+	}
+	if !used {
+		return fmt.Errorf("invalid two-factor code")
+	}
+
+	return nil
+}
+
+// verifyTOTP also remembers the matched time step, so a code that was
+// observed once cannot be replayed within its validity window
+func (s *service) verifyTOTP(ctx context.Context, userInfo *user.User, code string) error {
+	step, ok := utils.ValidateTOTP(*userInfo.TOTPSecret, code, time.Now())
+	if !ok {
+		return fmt.Errorf("invalid two-factor code")
+	}
+
+	key := fmt.Sprintf("totp:used:%s:%d", userInfo.ID, step)
+	fresh, err := s.rdb.SetNX(ctx, key, 1, 2*time.Minute).Result()
+	if err != nil {
+		return fmt.Errorf("verifyTOTP: %v", err)
+	}
+	if !fresh {
+		return fmt.Errorf("invalid two-factor code")
+	}
+
+	return nil
+}
+
+func (s *service) issueRecoveryCodes(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	now := time.Now()
+	plain := make([]string, recoveryCodeCount)
+	codes := make([]*RecoveryCode, recoveryCodeCount)
+
+	for i := range plain {
+		buf := make([]byte, 5)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, fmt.Errorf("issueRecoveryCodes: %v", err)
+		}
+		raw := strings.ToLower(base32.StdEncoding.EncodeToString(buf))
+		plain[i] = raw[:4] + "-" + raw[4:]
+		codes[i] = &RecoveryCode{
+			UserID:    userID,
+			CodeHash:  utils.HashToken(raw),
+			CreatedAt: now,
+		}
+	}
+
+	if err := s.repo.ReplaceRecoveryCodes(ctx, userID, codes); err != nil {
+		return nil, err
+	}
+
+	return plain, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.ReplaceAll(code, "-", "")
+	return strings.ReplaceAll(code, " ", "")
+}
@@ -0,0 +1,82 @@
+package twofactor
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"testing"
+	"time"
+
+	"task-management/internal/testutil"
+	"task-management/internal/user"
+
+	"github.com/google/uuid"
+)
+
+const secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// currentCode computes the code an authenticator app shows right now
+func currentCode(t *testing.T) string {
+	t.Helper()
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(time.Now().Unix())/30)
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%06d", value%1000000)
+}
+
+func enrolledUser() *user.User {
+	s := secret
+	now := time.Now()
+	return &user.User{ID: uuid.New(), TOTPSecret: &s, TOTPEnabledAt: &now}
+}
+
+func TestVerifyTOTPRejectsReplayedCode(t *testing.T) {
+	s := &service{rdb: testutil.NewRedis()}
+	ctx := context.Background()
+	u := enrolledUser()
+	code := currentCode(t)
+
+	if err := s.verifyTOTP(ctx, u, code); err != nil {
+		t.Fatalf("first use: %v", err)
+	}
+	err := s.verifyTOTP(ctx, u, code)
+	if err == nil || err.Error() != "invalid two-factor code" {
+		t.Fatalf("replay: got %v, want invalid two-factor code", err)
+	}
+}
+
+func TestVerifyTOTPReplayGuardIsPerUser(t *testing.T) {
+	s := &service{rdb: testutil.NewRedis()}
+	ctx := context.Background()
+	code := currentCode(t)
+
+	// two accounts sharing a secret must not use up each other's codes
+	if err := s.verifyTOTP(ctx, enrolledUser(), code); err != nil {
+		t.Fatalf("first user: %v", err)
+	}
+	if err := s.verifyTOTP(ctx, enrolledUser(), code); err != nil {
+		t.Fatalf("second user: %v", err)
+	}
+}
+
+func TestVerifyTOTPRejectsWrongCode(t *testing.T) {
+	s := &service{rdb: testutil.NewRedis()}
+	code := "000000"
+	if code == currentCode(t) {
+		code = "111111"
+	}
+	if err := s.verifyTOTP(context.Background(), enrolledUser(), code); err == nil {
+		t.Fatal("wrong code accepted")
+	}
+}
@@ -1,6 +1,11 @@
 package user
 
 import (
+	"errors"
+	"strings"
+
+	"task-management/internal/password"
+
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -64,6 +69,128 @@ func (controller *Controller) UpdateMyProfile(c *gin.Context) {
 	}
 
 	user, err := controller.service.UpdateProfile(c.Request.Context(), userUUID, &dto)
+	if err != nil {
+		if err.Error() == "email already in use" || err.Error() == "handle already in use" {
+			c.IndentedJSON(409, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		if strings.HasPrefix(err.Error(), "invalid handle") {
+			c.IndentedJSON(400, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		c.IndentedJSON(500, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.IndentedJSON(200, user)
+}
+
+func (controller *Controller) ChangePassword(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.IndentedJSON(401, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	userUUID := userID.(uuid.UUID)
+	sessionID, _ := c.Get("sessionID")
+	sessionUUID, _ := sessionID.(uuid.UUID)
+
+	var dto ChangePasswordRequest
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.IndentedJSON(400, gin.H{
+			"error": "invalid request body",
+		})
+		return
+	}
+
+	err := controller.service.ChangePassword(c.Request.Context(), userUUID, sessionUUID, &dto)
+	if err != nil {
+		var rejected *password.PolicyError
+		if errors.As(err, &rejected) {
+			c.IndentedJSON(422, gin.H{
+				"error": rejected.Error(),
+			})
+			return
+		}
+		if err.Error() == "invalid current password" {
+			c.IndentedJSON(401, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		c.IndentedJSON(500, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.IndentedJSON(200, gin.H{"message": "password changed, other sessions have been logged out"})
+}
+
+func (controller *Controller) RequestEmailChange(c *gin.Context) {
+	userID, ok := c.Get("userID")
+	if !ok {
+		c.IndentedJSON(401, gin.H{
+			"error": "unauthorized",
+		})
+		return
+	}
+
+	userUUID := userID.(uuid.UUID)
+
+	var dto ChangeEmailRequest
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.IndentedJSON(400, gin.H{
+			"error": "invalid request body",
+		})
+		return
+	}
+
+	err := controller.service.RequestEmailChange(c.Request.Context(), userUUID, &dto)
+	if err != nil {
+		switch err.Error() {
+		case "invalid current password":
+			c.IndentedJSON(401, gin.H{
+				"error": err.Error(),
+			})
+		case "email already in use":
+			c.IndentedJSON(409, gin.H{
+				"error": err.Error(),
+			})
+		case "email unchanged":
+			c.IndentedJSON(400, gin.H{
+				"error": err.Error(),
+			})
+		default:
+			c.IndentedJSON(500, gin.H{
+				"error": err.Error(),
+			})
+		}
+		return
+	}
+
+	c.IndentedJSON(202, gin.H{"message": "a confirmation link has been sent to the new address"})
+}
+
+func (controller *Controller) ConfirmEmailChange(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		c.IndentedJSON(400, gin.H{
+			"error": "token is required",
+		})
+		return
+	}
+
+	user, err := controller.service.ConfirmEmailChange(c.Request.Context(), token)
 	if err != nil {
 		if err.Error() == "email already in use" {
 			c.IndentedJSON(409, gin.H{
@@ -71,6 +198,12 @@ func (controller *Controller) UpdateMyProfile(c *gin.Context) {
 			})
 			return
 		}
+		if strings.Contains(err.Error(), "token invalid or expired") {
+			c.IndentedJSON(400, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
 		c.IndentedJSON(500, gin.H{
 			"error": err.Error(),
 		})
@@ -4,12 +4,36 @@ import "github.com/google/uuid"
 
 type UpdateUserRequest struct {
 	Name string `json:"name" binding:"required,min=1,max=255"`
-	// Email string `json:"email" binding:"omitempty,email,max=255"`
+	// Handle is left unchanged when omitted and removed when empty
+	Handle *string `json:"handle" binding:"omitempty,max=30"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required,min=1,max=255"`
+	NewPassword     string `json:"new_password" binding:"required,min=1,max=255"`
+}
+
+// ChangeEmailRequest starts an email change; the address is only switched
+// once the link mailed to the new address is opened
+type ChangeEmailRequest struct {
+	Email    string `json:"email" binding:"required,email,max=255"`
+	Password string `json:"password" binding:"required,min=1,max=255"`
 }
 
 type UserResponse struct {
-	ID    uuid.UUID `json:"id"`
-	Name  string    `json:"name"`
-	Email string    `json:"email"`
+	ID            uuid.UUID `json:"id"`
+	Name          string    `json:"name"`
+	Email         string    `json:"email"`
+	Handle        *string   `json:"handle"`
+	EmailVerified bool      `json:"email_verified"`
 }
 
+func ToUserResponse(user *User) *UserResponse {
+	return &UserResponse{
+		ID:            user.ID,
+		Name:          user.Name,
+		Email:         user.Email,
+		Handle:        user.Handle,
+		EmailVerified: user.EmailVerifiedAt != nil,
+	}
+}
@@ -7,11 +7,18 @@ import (
 )
 
 type User struct {
-	ID           uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
-	Name         string    `gorm:"type:varchar(255);not null"`
-	PasswordHash string    `gorm:"type:varchar(255);not null"`
-	Email        string    `gorm:"type:varchar(255);not null"`
-	CreatedAt    time.Time `gorm:"type:timestamp"`
+	ID              uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	Name            string     `gorm:"type:varchar(255);not null"`
+	PasswordHash    string     `gorm:"type:varchar(255);not null"`
+	Email           string     `gorm:"type:varchar(255);not null"`
+	Handle          *string    `gorm:"type:varchar(30)"`
+	EmailVerifiedAt *time.Time `gorm:"type:timestamp"`
+	TOTPSecret      *string    `gorm:"column:totp_secret;type:varchar(64)"`
+	TOTPEnabledAt   *time.Time `gorm:"column:totp_enabled_at;type:timestamp"`
+	CreatedAt       time.Time  `gorm:"type:timestamp"`
+	// NotificationPreferences is a JSON object of notification type to
+	// whether the user wants it; types left out are on
+	NotificationPreferences string `gorm:"type:jsonb;not null;default:'{}'"`
 }
 
 func (User) TableName() string {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -30,9 +31,11 @@ func (r *Repository) FindByID(ctx context.Context, id uuid.UUID) (*User, error)
 	return &user, nil
 }
 
+// FindByEmail matches the address case-insensitively, like every other email
+// lookup, so Alice@x.com and alice@x.com are the same account
 func (r *Repository) FindByEmail(ctx context.Context, email string) (*User, error) {
 	var user User
-	err := r.db.Where("email = ?", email).First(&user).Error
+	err := r.db.Where("LOWER(email) = LOWER(?)", email).First(&user).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, fmt.Errorf("user not found")
@@ -61,9 +64,84 @@ func (r *Repository) Update(ctx context.Context, user *User) error {
 
 func (r *Repository) EmailAvailale(ctx context.Context, email string) (bool, error) {
 	var count int64
-	err := r.db.WithContext(ctx).Model(&User{}).Where("email = ?", email).Count(&count).Error
+	err := r.db.WithContext(ctx).Model(&User{}).Where("LOWER(email) = LOWER(?)", email).Count(&count).Error
 	if err != nil {
 		return false, fmt.Errorf("EmailExists: %v", err)
 	}
 	return count < 1, nil
 }
+
+// HandleAvailable reports whether nobody but userID uses the handle
+func (r *Repository) HandleAvailable(ctx context.Context, handle string, userID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&User{}).Where("handle = ? AND id <> ?", handle, userID).Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("HandleAvailable: %v", err)
+	}
+	return count < 1, nil
+}
+
+// SetHandle sets or, with nil, removes the user's handle
+func (r *Repository) SetHandle(ctx context.Context, id uuid.UUID, handle *string) error {
+	err := r.db.WithContext(ctx).Model(&User{}).Where("id = ?", id).Update("handle", handle).Error
+	if err != nil {
+		return fmt.Errorf("SetHandle: %v", err)
+	}
+	return nil
+}
+
+func (r *Repository) UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string) error {
+	err := r.db.WithContext(ctx).Model(&User{}).Where("id = ?", id).Update("password_hash", passwordHash).Error
+	if err != nil {
+		return fmt.Errorf("UpdatePassword: %v", err)
+	}
+	return nil
+}
+
+// UpdateEmail switches the address and marks it verified, since changes are
+// only applied after the new address confirmed them
+func (r *Repository) UpdateEmail(ctx context.Context, id uuid.UUID, email string) error {
+	err := r.db.WithContext(ctx).Model(&User{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"email": email, "email_verified_at": time.Now()}).Error
+	if err != nil {
+		return fmt.Errorf("UpdateEmail: %v", err)
+	}
+	return nil
+}
+
+func (r *Repository) MarkEmailVerified(ctx context.Context, id uuid.UUID) error {
+	err := r.db.WithContext(ctx).Model(&User{}).
+		Where("id = ? AND email_verified_at IS NULL", id).
+		Update("email_verified_at", time.Now()).Error
+	if err != nil {
+		return fmt.Errorf("MarkEmailVerified: %v", err)
+	}
+	return nil
+}
+
+// SetTOTPSecret stores a pending secret; it only protects the account once EnableTOTP is called
+func (r *Repository) SetTOTPSecret(ctx context.Context, id uuid.UUID, secret string) error {
+	err := r.db.WithContext(ctx).Model(&User{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"totp_secret": secret, "totp_enabled_at": nil}).Error
+	if err != nil {
+		return fmt.Errorf("SetTOTPSecret: %v", err)
+	}
+	return nil
+}
+
+func (r *Repository) EnableTOTP(ctx context.Context, id uuid.UUID) error {
+	err := r.db.WithContext(ctx).Model(&User{}).Where("id = ?", id).Update("totp_enabled_at", time.Now()).Error
+	if err != nil {
+		return fmt.Errorf("EnableTOTP: %v", err)
+	}
+	return nil
+}
+
+func (r *Repository) DisableTOTP(ctx context.Context, id uuid.UUID) error {
+	err := r.db.WithContext(ctx).Model(&User{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"totp_secret": nil, "totp_enabled_at": nil}).Error
+	if err != nil {
+		return fmt.Errorf("DisableTOTP: %v", err)
+	}
+	return nil
+}
@@ -5,12 +5,18 @@ import (
 )
 
 func RegisterRoutes(group *gin.RouterGroup, controller *Controller,
-	 authMw gin.HandlerFunc, rateLimitMw gin.HandlerFunc) {
+	 authMw gin.HandlerFunc, scopeMw gin.HandlerFunc, rateLimitMw gin.HandlerFunc) {
+	// opened from the confirmation mail, so it cannot require a login
+	group.GET("/users/email/confirm", rateLimitMw, controller.ConfirmEmailChange)
+
 	users := group.Group("/users")
 	users.Use(authMw) 
+	users.Use(scopeMw)
 	users.Use(rateLimitMw)
 	{
 		users.GET("/me", controller.MyProfile)
 		users.PUT("/me", controller.UpdateMyProfile)
+		users.PUT("/me/password", controller.ChangePassword)
+		users.POST("/me/email", controller.RequestEmailChange)
 	}
 }
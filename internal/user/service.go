@@ -3,6 +3,17 @@ package user
 import (
 	"context"
 	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"task-management/internal/config"
+	"task-management/internal/mailer"
+	"task-management/internal/password"
+	"task-management/internal/session"
+	"task-management/internal/usertoken"
+	"task-management/internal/utils"
 
 	"github.com/google/uuid"
 )
@@ -10,14 +21,33 @@ import (
 type Service interface {
 	GetProfile(ctx context.Context, id uuid.UUID) (*UserResponse, error)
 	UpdateProfile(ctx context.Context, id uuid.UUID, dto *UpdateUserRequest) (*UserResponse, error)
+	// ChangePassword keeps the calling session and logs out every other one
+	ChangePassword(ctx context.Context, id uuid.UUID, currentSessionID uuid.UUID, dto *ChangePasswordRequest) error
+	RequestEmailChange(ctx context.Context, id uuid.UUID, dto *ChangeEmailRequest) error
+	ConfirmEmailChange(ctx context.Context, token string) (*UserResponse, error)
 }
 
 type service struct {
-	repo *Repository
+	config    *config.Config
+	repo      *Repository
+	tokenRepo *usertoken.Repository
+	mailer    mailer.Mailer
+	hasher    password.Hasher
+	policy    *password.Policy
+	sessions  session.Service
 }
 
-func NewService(repo *Repository) Service {
-	return &service{repo: repo}
+func NewService(config *config.Config, repo *Repository, tokenRepo *usertoken.Repository, mailer mailer.Mailer,
+	hasher password.Hasher, policy *password.Policy, sessions session.Service) Service {
+	return &service{
+		config:    config,
+		repo:      repo,
+		tokenRepo: tokenRepo,
+		mailer:    mailer,
+		hasher:    hasher,
+		policy:    policy,
+		sessions:  sessions,
+	}
 }
 
 func (service *service) GetProfile(ctx context.Context, id uuid.UUID) (*UserResponse, error) {
@@ -26,7 +56,7 @@ func (service *service) GetProfile(ctx context.Context, id uuid.UUID) (*UserResp
 		return nil, fmt.Errorf("GetProfile: %v", err)
 	}
 
-	return &UserResponse{user.ID, user.Name, user.Email}, nil
+	return ToUserResponse(user), nil
 }
 
 func (service *service) UpdateProfile(ctx context.Context, id uuid.UUID, dto *UpdateUserRequest) (*UserResponse, error) {
@@ -35,12 +65,192 @@ func (service *service) UpdateProfile(ctx context.Context, id uuid.UUID, dto *Up
 		return nil, fmt.Errorf("GetProfile: %v", err)
 	}
 
+	if dto.Handle != nil {
+		handle, err := service.checkHandle(ctx, id, *dto.Handle)
+		if err != nil {
+			return nil, err
+		}
+		if err := service.repo.SetHandle(ctx, id, handle); err != nil {
+			return nil, fmt.Errorf("UpdateProfile: %v", err)
+		}
+		user.Handle = handle
+	}
+
 	user.Name = dto.Name
 	err = service.repo.Update(ctx, user)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update profile - UpdateProfile: %v", err)
 	}
 
-	return &UserResponse{user.ID, user.Name, user.Email}, nil
+	return ToUserResponse(user), nil
+}
+
+// handlePattern allows letters, digits and underscores, with single dots or
+// dashes in between so a handle never ends in punctuation
+var handlePattern = regexp.MustCompile(`^[a-z0-9_]+([.-][a-z0-9_]+)*$`)
+
+// checkHandle normalizes a requested handle, nil meaning none
+func (service *service) checkHandle(ctx context.Context, id uuid.UUID, raw string) (*string, error) {
+	handle := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(raw), "@"))
+	if handle == "" {
+		return nil, nil
+	}
+	if len(handle) < 2 || !handlePattern.MatchString(handle) {
+		return nil, fmt.Errorf("invalid handle: use 2 to 30 letters, digits, underscores, dots or dashes")
+	}
+
+	available, err := service.repo.HandleAvailable(ctx, handle, id)
+	if err != nil {
+		return nil, fmt.Errorf("UpdateProfile: %v", err)
+	}
+	if !available {
+		return nil, fmt.Errorf("handle already in use")
+	}
+	return &handle, nil
+}
+
+func (service *service) ChangePassword(ctx context.Context, id uuid.UUID, currentSessionID uuid.UUID, dto *ChangePasswordRequest) error {
+	user, err := service.repo.FindByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("ChangePassword: %v", err)
+	}
+
+	if !service.hasher.Verify(dto.CurrentPassword, user.PasswordHash) {
+		return fmt.Errorf("invalid current password")
+	}
+
+	if err := service.policy.Validate(dto.NewPassword); err != nil {
+		return fmt.Errorf("ChangePassword: %w", err)
+	}
+
+	pwHash, err := service.hasher.Hash(dto.NewPassword)
+	if err != nil {
+		return fmt.Errorf("failed to generate password hash - ChangePassword: %v", err)
+	}
+
+	if err := service.repo.UpdatePassword(ctx, id, pwHash); err != nil {
+		return fmt.Errorf("ChangePassword: %v", err)
+	}
+
+	// refresh tokens of the revoked sessions stop working as well
+	if err := service.sessions.RevokeAllExcept(ctx, id, currentSessionID); err != nil {
+		return fmt.Errorf("ChangePassword: %v", err)
+	}
+
+	return nil
+}
+
+func (service *service) RequestEmailChange(ctx context.Context, id uuid.UUID, dto *ChangeEmailRequest) error {
+	user, err := service.repo.FindByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("RequestEmailChange: %v", err)
+	}
+
+	if !service.hasher.Verify(dto.Password, user.PasswordHash) {
+		return fmt.Errorf("invalid current password")
+	}
+
+	newEmail := strings.TrimSpace(dto.Email)
+	if newEmail == user.Email {
+		return fmt.Errorf("email unchanged")
+	}
+
+	available, err := service.repo.EmailAvailale(ctx, newEmail)
+	if err != nil {
+		return fmt.Errorf("RequestEmailChange: %v", err)
+	}
+	if !available {
+		return fmt.Errorf("email already in use")
+	}
+
+	// only the most recent request can be confirmed
+	if err := service.tokenRepo.InvalidateForUser(ctx, id, usertoken.PurposeEmailChange); err != nil {
+		return fmt.Errorf("RequestEmailChange: %v", err)
+	}
+
+	token, tokenHash, err := utils.NewOpaqueToken()
+	if err != nil {
+		return fmt.Errorf("RequestEmailChange: %v", err)
+	}
+
+	now := time.Now()
+	err = service.tokenRepo.Create(ctx, &usertoken.UserToken{
+		UserID:    id,
+		Purpose:   usertoken.PurposeEmailChange,
+		TokenHash: tokenHash,
+		Payload:   &newEmail,
+		ExpiresAt: now.Add(time.Hour * time.Duration(service.config.Auth.VerificationTTLHours)),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return fmt.Errorf("RequestEmailChange: %v", err)
+	}
+
+	err = service.mailer.Send(ctx, &mailer.Message{
+		To:      newEmail,
+		Subject: "Confirm your new email address",
+		Body: fmt.Sprintf("Hi %s,\n\nPlease confirm that you want to use this address for your account "+
+			"by opening the link below:\n\n%s/users/email/confirm?token=%s\n\nThe link expires in %d hours. "+
+			"Until then your account keeps using %s.\n",
+			user.Name, service.config.Server.PublicURL, token, service.config.Auth.VerificationTTLHours, user.Email),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send confirmation mail - RequestEmailChange: %v", err)
+	}
+
+	return nil
 }
 
+func (service *service) ConfirmEmailChange(ctx context.Context, token string) (*UserResponse, error) {
+	stored, err := service.tokenRepo.FindValid(ctx, usertoken.PurposeEmailChange, utils.HashToken(token))
+	if err != nil {
+		return nil, fmt.Errorf("ConfirmEmailChange: %v", err)
+	}
+	if stored.Payload == nil {
+		return nil, fmt.Errorf("ConfirmEmailChange: token invalid or expired")
+	}
+
+	consumed, err := service.tokenRepo.MarkUsed(ctx, stored.ID)
+	if err != nil {
+		return nil, fmt.Errorf("ConfirmEmailChange: %v", err)
+	}
+	if !consumed {
+		return nil, fmt.Errorf("ConfirmEmailChange: token invalid or expired")
+	}
+
+	user, err := service.repo.FindByID(ctx, stored.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("ConfirmEmailChange: %v", err)
+	}
+
+	// the address may have been taken since the change was requested
+	newEmail := *stored.Payload
+	available, err := service.repo.EmailAvailale(ctx, newEmail)
+	if err != nil {
+		return nil, fmt.Errorf("ConfirmEmailChange: %v", err)
+	}
+	if !available {
+		return nil, fmt.Errorf("email already in use")
+	}
+
+	if err := service.repo.UpdateEmail(ctx, user.ID, newEmail); err != nil {
+		return nil, fmt.Errorf("ConfirmEmailChange: %v", err)
+	}
+
+	oldEmail := user.Email
+	err = service.mailer.Send(ctx, &mailer.Message{
+		To:      oldEmail,
+		Subject: "Your email address was changed",
+		Body: fmt.Sprintf("Hi %s,\n\nThe email address of your account was changed to %s. "+
+			"If you did not do this, reset your password and contact support.\n",
+			user.Name, newEmail),
+	})
+	if err != nil {
+		log.Printf("failed to notify previous address - ConfirmEmailChange: %v", err)
+	}
+
+	now := time.Now()
+	user.Email = newEmail
+	user.EmailVerifiedAt = &now
+	return ToUserResponse(user), nil
+}
@@ -0,0 +1,30 @@
+package usertoken
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Purposes a single-use token can be issued for
+const (
+	PurposePasswordReset     = "password_reset"
+	PurposeEmailVerification = "email_verification"
+	// PurposeEmailChange carries the requested address in Payload
+	PurposeEmailChange = "email_change"
+)
+
+type UserToken struct {
+	ID        int64      `gorm:"primaryKey;autoIncrement"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index"`
+	Purpose   string     `gorm:"type:varchar(50);not null"`
+	TokenHash string     `gorm:"type:varchar(64);not null;uniqueIndex"`
+	Payload   *string    `gorm:"type:text"`
+	ExpiresAt time.Time  `gorm:"type:timestamp;not null"`
+	UsedAt    *time.Time `gorm:"type:timestamp"`
+	CreatedAt time.Time  `gorm:"type:timestamp;not null"`
+}
+
+func (UserToken) TableName() string {
+	return "user_token"
+}
@@ -0,0 +1,64 @@
+package usertoken
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) Create(ctx context.Context, token *UserToken) error {
+	err := r.db.WithContext(ctx).Create(token).Error
+	if err != nil {
+		return fmt.Errorf("usertoken - Create: %v", err)
+	}
+	return nil
+}
+
+// FindValid returns an unused, unexpired token for the given purpose
+func (r *Repository) FindValid(ctx context.Context, purpose string, tokenHash string) (*UserToken, error) {
+	var token UserToken
+	err := r.db.WithContext(ctx).
+		Where("purpose = ? AND token_hash = ? AND used_at IS NULL AND expires_at > ?", purpose, tokenHash, time.Now()).
+		First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("token invalid or expired")
+		}
+		return nil, fmt.Errorf("FindValid: %v", err)
+	}
+	return &token, nil
+}
+
+// MarkUsed consumes a token. It reports false when someone else consumed it first.
+func (r *Repository) MarkUsed(ctx context.Context, id int64) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&UserToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	if res.Error != nil {
+		return false, fmt.Errorf("MarkUsed: %v", res.Error)
+	}
+	return res.RowsAffected == 1, nil
+}
+
+// InvalidateForUser consumes every outstanding token of a purpose, so only the latest one works
+func (r *Repository) InvalidateForUser(ctx context.Context, userID uuid.UUID, purpose string) error {
+	err := r.db.WithContext(ctx).Model(&UserToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", time.Now()).Error
+	if err != nil {
+		return fmt.Errorf("InvalidateForUser: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func denylistKey(jti string) string {
+	return "denylist:" + jti
+}
+
+// RevokeToken denylists an access token by its jti until it would have expired anyway.
+func RevokeToken(ctx context.Context, rdb *redis.Client, jti string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+
+	if err := rdb.Set(ctx, denylistKey(jti), 1, ttl).Err(); err != nil {
+		return fmt.Errorf("RevokeToken: %v", err)
+	}
+	return nil
+}
+
+func IsTokenRevoked(ctx context.Context, rdb *redis.Client, jti string) (bool, error) {
+	count, err := rdb.Exists(ctx, denylistKey(jti)).Result()
+	if err != nil {
+		return false, fmt.Errorf("IsTokenRevoked: %v", err)
+	}
+	return count > 0, nil
+}
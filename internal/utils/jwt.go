@@ -1,7 +1,18 @@
 package utils
 
 import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
 	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"task-management/internal/config"
 	"time"
 
@@ -9,25 +20,199 @@ import (
 	"github.com/google/uuid"
 )
 
+// Token purposes. Only access tokens are accepted by the auth middleware.
+const (
+	PurposeAccess             = "access"
+	PurposeTwoFactorChallenge = "2fa_challenge"
+)
+
 type Claims struct {
-	UserID uuid.UUID `json:"userID"`
+	UserID    uuid.UUID `json:"userID"`
+	SessionID uuid.UUID `json:"sid"`
+	Purpose   string    `json:"purpose"`
 	jwt.RegisteredClaims
 }
 
-func CreateToken(config *config.Config, userID uuid.UUID) (string, error) {
-	expTime := time.Now().Add(time.Hour * time.Duration(config.JWT.ExpireHours))
+type verificationKey struct {
+	method jwt.SigningMethod
+	key    interface{}
+}
+
+// KeySet signs and verifies our JWTs. With JWT_KEYS_DIR unset it falls back
+// to HS256 with JWT_SECRET; otherwise the active key signs and every key in
+// the directory verifies, so tokens survive a key rotation.
+type KeySet struct {
+	config    *config.Config
+	activeKID string
+	method    jwt.SigningMethod
+	signKey   interface{}
+	verify    map[string]verificationKey
+}
+
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+func NewKeySet(config *config.Config) (*KeySet, error) {
+	if config.JWT.KeysDir == "" {
+		return &KeySet{
+			config:  config,
+			method:  jwt.SigningMethodHS256,
+			signKey: []byte(config.JWT.Secret),
+			verify: map[string]verificationKey{
+				"": {method: jwt.SigningMethodHS256, key: []byte(config.JWT.Secret)},
+			},
+		}, nil
+	}
+
+	keys := &KeySet{config: config, verify: map[string]verificationKey{}}
+	privateKeys := map[string]crypto.Signer{}
+
+	paths, err := filepath.Glob(filepath.Join(config.JWT.KeysDir, "*.pem"))
+	if err != nil {
+		return nil, fmt.Errorf("NewKeySet: %v", err)
+	}
+
+	for _, path := range paths {
+		name := filepath.Base(path)
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("NewKeySet: %v", err)
+		}
+
+		if strings.HasSuffix(name, ".pub.pem") {
+			kid := strings.TrimSuffix(name, ".pub.pem")
+			public, err := parsePublicKey(raw)
+			if err != nil {
+				return nil, fmt.Errorf("NewKeySet - %s: %v", name, err)
+			}
+			if err := keys.addVerificationKey(kid, public); err != nil {
+				return nil, fmt.Errorf("NewKeySet - %s: %v", name, err)
+			}
+			continue
+		}
+
+		kid := strings.TrimSuffix(name, ".pem")
+		private, err := parsePrivateKey(raw)
+		if err != nil {
+			return nil, fmt.Errorf("NewKeySet - %s: %v", name, err)
+		}
+		if err := keys.addVerificationKey(kid, private.Public()); err != nil {
+			return nil, fmt.Errorf("NewKeySet - %s: %v", name, err)
+		}
+		privateKeys[kid] = private
+	}
+
+	activeKID := config.JWT.ActiveKID
+	if activeKID == "" && len(privateKeys) == 1 {
+		for kid := range privateKeys {
+			activeKID = kid
+		}
+	}
+
+	signer, ok := privateKeys[activeKID]
+	if !ok {
+		return nil, fmt.Errorf("NewKeySet: no private key for active kid %q in %s", activeKID, config.JWT.KeysDir)
+	}
+
+	keys.activeKID = activeKID
+	keys.method = keys.verify[activeKID].method
+	keys.signKey = signer
+	return keys, nil
+}
+
+func (k *KeySet) addVerificationKey(kid string, public crypto.PublicKey) error {
+	switch public.(type) {
+	case *rsa.PublicKey:
+		k.verify[kid] = verificationKey{method: jwt.SigningMethodRS256, key: public}
+	case ed25519.PublicKey:
+		k.verify[kid] = verificationKey{method: jwt.SigningMethodEdDSA, key: public}
+	default:
+		return fmt.Errorf("unsupported key type %T", public)
+	}
+	return nil
+}
+
+func parsePrivateKey(raw []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found")
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("unsupported private key type %T", key)
+		}
+		return signer, nil
+	}
+
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported private key encoding: %v", err)
+	}
+	return key, nil
+}
+
+func parsePublicKey(raw []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found")
+	}
+
+	if key, err := x509.ParsePKIXPublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS1PublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported public key encoding: %v", err)
+	}
+	return key, nil
+}
+
+func (k *KeySet) CreateToken(userID uuid.UUID, sessionID uuid.UUID) (string, error) {
+	return k.createToken(userID, sessionID, PurposeAccess, time.Minute*time.Duration(k.config.JWT.AccessExpireMinutes))
+}
+
+// CreateChallengeToken proves the password step of a two-factor login succeeded
+func (k *KeySet) CreateChallengeToken(userID uuid.UUID) (string, error) {
+	return k.createToken(userID, uuid.Nil, PurposeTwoFactorChallenge, time.Minute*time.Duration(k.config.Auth.ChallengeTTLMinutes))
+}
+
+func (k *KeySet) createToken(userID uuid.UUID, sessionID uuid.UUID, purpose string, ttl time.Duration) (string, error) {
+	expTime := time.Now().Add(ttl)
 
 	claims := &Claims{
 		userID,
+		sessionID,
+		purpose,
 		jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Issuer:    k.config.JWT.Issuer,
+			Audience:  jwt.ClaimStrings{k.config.JWT.Audience},
 			ExpiresAt: jwt.NewNumericDate(expTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	
-	tokenString, err := token.SignedString([]byte(config.JWT.Secret))
+	token := jwt.NewWithClaims(k.method, claims)
+	if k.activeKID != "" {
+		token.Header["kid"] = k.activeKID
+	}
+
+	tokenString, err := token.SignedString(k.signKey)
 	if err != nil {
 		return "", fmt.Errorf("CreateToken - %v", err)
 	}
@@ -35,23 +220,67 @@ func CreateToken(config *config.Config, userID uuid.UUID) (string, error) {
 	return tokenString, nil
 }
 
-func ParseToken(tokenString string, config config.Config) (*Claims, error) {
+func (k *KeySet) ParseToken(tokenString string) (*Claims, error) {
 	claims := &Claims{}
 
 	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := k.verify[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown key id: %q", kid)
+		}
+		if token.Method.Alg() != key.method.Alg() {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
-		return []byte(config.JWT.Secret), nil
-	})
+		return key.key, nil
+	},
+		jwt.WithIssuer(k.config.JWT.Issuer),
+		jwt.WithAudience(k.config.JWT.Audience),
+		jwt.WithExpirationRequired(),
+	)
 
 	if err != nil {
 		return nil, fmt.Errorf("ParseToken: %v", err)
 	}
 
 	if !token.Valid {
-		return nil, fmt.Errorf("invalid token - ParseToken: %v")
+		return nil, fmt.Errorf("invalid token - ParseToken")
 	}
 
 	return claims, nil
 }
+
+// JWKS publishes the public verification keys. It is empty in HS256 mode
+// because a shared secret can't be published.
+func (k *KeySet) JWKS() *JWKS {
+	kids := make([]string, 0, len(k.verify))
+	for kid := range k.verify {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	set := &JWKS{Keys: []JWK{}}
+	for _, kid := range kids {
+		switch key := k.verify[kid].key.(type) {
+		case *rsa.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				Kty: "RSA",
+				Kid: kid,
+				Use: "sig",
+				Alg: jwt.SigningMethodRS256.Alg(),
+				N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				Kty: "OKP",
+				Kid: kid,
+				Use: "sig",
+				Alg: jwt.SigningMethodEdDSA.Alg(),
+				Crv: "Ed25519",
+				X:   base64.RawURLEncoding.EncodeToString(key),
+			})
+		}
+	}
+	return set
+}
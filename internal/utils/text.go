@@ -0,0 +1,25 @@
+package utils
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Truncate shortens s to at most n characters for a VARCHAR(n) column. It
+// never splits a multi-byte character and drops invalid UTF-8, which
+// Postgres would refuse to store.
+func Truncate(s string, n int) string {
+	s = strings.ToValidUTF8(s, "")
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+
+	count := 0
+	for i := range s {
+		if count == n {
+			return s[:i]
+		}
+		count++
+	}
+	return s
+}
@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		n    int
+		want string
+	}{
+		{"short", "curl/8.0", 512, "curl/8.0"},
+		{"exact", "abcde", 5, "abcde"},
+		{"ascii", "abcdef", 5, "abcde"},
+		{"counts characters, not bytes", "ééééé", 5, "ééééé"},
+		{"cuts between characters", "日本語のブラウザ", 3, "日本語"},
+		{"emoji", "a😀b😀c", 4, "a😀b😀"},
+		{"invalid utf-8 dropped", "ab\xffcd", 3, "abc"},
+		{"empty", "", 5, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Truncate(tt.in, tt.n)
+			if got != tt.want {
+				t.Errorf("Truncate(%q, %d) = %q, want %q", tt.in, tt.n, got, tt.want)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("Truncate(%q, %d) is not valid UTF-8", tt.in, tt.n)
+			}
+		})
+	}
+}
+
+func TestTruncateLongUserAgent(t *testing.T) {
+	agent := strings.Repeat("ü", 600)
+	got := Truncate(agent, 512)
+	if n := utf8.RuneCountInString(got); n != 512 {
+		t.Errorf("kept %d characters, want 512", n)
+	}
+}
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_refresh_token_family_id;
+DROP INDEX IF EXISTS idx_refresh_token_user_id;
+DROP TABLE IF EXISTS refresh_token;
@@ -0,0 +1,13 @@
+-- Rotating refresh tokens; every token issued from the same login shares a family
+CREATE TABLE refresh_token (
+    id BIGSERIAL PRIMARY KEY,
+    user_id UUID REFERENCES app_user(id) ON DELETE CASCADE NOT NULL,
+    family_id UUID NOT NULL,
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_refresh_token_user_id ON refresh_token(user_id);
+CREATE INDEX idx_refresh_token_family_id ON refresh_token(family_id);
@@ -0,0 +1,13 @@
+-- Single-use, expiring tokens mailed to users (password reset, ...)
+CREATE TABLE user_token (
+    id BIGSERIAL PRIMARY KEY,
+    user_id UUID REFERENCES app_user(id) ON DELETE CASCADE NOT NULL,
+    purpose VARCHAR(50) NOT NULL,
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    payload TEXT,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_user_token_user_id ON user_token(user_id);
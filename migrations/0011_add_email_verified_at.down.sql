@@ -0,0 +1 @@
+ALTER TABLE app_user DROP COLUMN IF EXISTS email_verified_at;
@@ -0,0 +1,4 @@
+ALTER TABLE app_user ADD COLUMN email_verified_at TIMESTAMP;
+
+-- Accounts from before verification existed count as verified
+UPDATE app_user SET email_verified_at = COALESCE(created_at, NOW()) WHERE email_verified_at IS NULL;
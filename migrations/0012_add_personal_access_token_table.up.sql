@@ -0,0 +1,13 @@
+-- Long-lived, scoped tokens for scripts and CI
+CREATE TABLE personal_access_token (
+    id BIGSERIAL PRIMARY KEY,
+    user_id UUID REFERENCES app_user(id) ON DELETE CASCADE NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    scopes VARCHAR(255) NOT NULL,
+    last_used_at TIMESTAMP,
+    expires_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_personal_access_token_user_id ON personal_access_token(user_id);
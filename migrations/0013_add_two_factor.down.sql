@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_recovery_code_user_id;
+DROP TABLE IF EXISTS recovery_code;
+
+ALTER TABLE app_user DROP COLUMN IF EXISTS totp_enabled_at;
+ALTER TABLE app_user DROP COLUMN IF EXISTS totp_secret;
@@ -0,0 +1,12 @@
+ALTER TABLE app_user ADD COLUMN totp_secret VARCHAR(64);
+ALTER TABLE app_user ADD COLUMN totp_enabled_at TIMESTAMP;
+
+CREATE TABLE recovery_code (
+    id BIGSERIAL PRIMARY KEY,
+    user_id UUID REFERENCES app_user(id) ON DELETE CASCADE NOT NULL,
+    code_hash VARCHAR(64) NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_recovery_code_user_id ON recovery_code(user_id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_login_event_user_id_created_at;
+DROP TABLE IF EXISTS login_event;
@@ -0,0 +1,12 @@
+-- Audit trail of every sign-in attempt; user_id is empty for unknown emails
+CREATE TABLE login_event (
+    id BIGSERIAL PRIMARY KEY,
+    user_id UUID REFERENCES app_user(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    ip_address VARCHAR(64),
+    user_agent VARCHAR(512),
+    outcome VARCHAR(32) NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_login_event_user_id_created_at ON login_event(user_id, created_at DESC);
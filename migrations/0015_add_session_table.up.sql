@@ -0,0 +1,13 @@
+-- One row per logged-in device; refresh token families are keyed by session id
+CREATE TABLE session (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID REFERENCES app_user(id) ON DELETE CASCADE NOT NULL,
+    device_name VARCHAR(255) NOT NULL,
+    ip_address VARCHAR(64),
+    user_agent VARCHAR(512),
+    created_at TIMESTAMP NOT NULL,
+    last_seen_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP
+);
+
+CREATE INDEX idx_session_user_id ON session(user_id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_external_identity_user_id;
+DROP TABLE IF EXISTS external_identity;
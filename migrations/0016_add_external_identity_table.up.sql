@@ -0,0 +1,12 @@
+-- Accounts at external OpenID Connect providers linked to local users
+CREATE TABLE external_identity (
+    id BIGSERIAL PRIMARY KEY,
+    user_id UUID REFERENCES app_user(id) ON DELETE CASCADE NOT NULL,
+    provider VARCHAR(50) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    UNIQUE(provider, subject)
+);
+
+CREATE INDEX idx_external_identity_user_id ON external_identity(user_id);
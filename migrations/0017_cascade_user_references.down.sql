@@ -0,0 +1,11 @@
+ALTER TABLE subtask DROP CONSTRAINT IF EXISTS subtask_assigned_to_fkey;
+ALTER TABLE subtask ADD CONSTRAINT subtask_assigned_to_fkey
+    FOREIGN KEY (assigned_to) REFERENCES app_user(id);
+
+ALTER TABLE subtask DROP CONSTRAINT IF EXISTS subtask_task_id_fkey;
+ALTER TABLE subtask ADD CONSTRAINT subtask_task_id_fkey
+    FOREIGN KEY (task_id) REFERENCES task(id);
+
+ALTER TABLE task DROP CONSTRAINT IF EXISTS task_project_id_fkey;
+ALTER TABLE task ADD CONSTRAINT task_project_id_fkey
+    FOREIGN KEY (project_id) REFERENCES project(id);
@@ -0,0 +1,14 @@
+-- Let projects take their tasks and subtasks with them, and let users be
+-- deleted without leaving dangling subtask assignments behind.
+-- Constraint names are the ones Postgres generated in 0002 and 0006.
+ALTER TABLE task DROP CONSTRAINT IF EXISTS task_project_id_fkey;
+ALTER TABLE task ADD CONSTRAINT task_project_id_fkey
+    FOREIGN KEY (project_id) REFERENCES project(id) ON DELETE CASCADE;
+
+ALTER TABLE subtask DROP CONSTRAINT IF EXISTS subtask_task_id_fkey;
+ALTER TABLE subtask ADD CONSTRAINT subtask_task_id_fkey
+    FOREIGN KEY (task_id) REFERENCES task(id) ON DELETE CASCADE;
+
+ALTER TABLE subtask DROP CONSTRAINT IF EXISTS subtask_assigned_to_fkey;
+ALTER TABLE subtask ADD CONSTRAINT subtask_assigned_to_fkey
+    FOREIGN KEY (assigned_to) REFERENCES app_user(id) ON DELETE SET NULL;
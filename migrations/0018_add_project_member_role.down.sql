@@ -0,0 +1 @@
+ALTER TABLE project_member DROP COLUMN IF EXISTS role;
@@ -0,0 +1,5 @@
+-- Roles inside a project. The owner is still project.owner_id; existing
+-- members become editors, which matches what they could do before.
+ALTER TABLE project_member
+    ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'editor'
+    CHECK (role IN ('viewer', 'editor', 'maintainer', 'admin'));
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_project_invitation_email;
+DROP INDEX IF EXISTS idx_project_invitation_project_id;
+DROP TABLE IF EXISTS project_invitation;
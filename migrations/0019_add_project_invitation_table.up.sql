@@ -0,0 +1,17 @@
+-- Invitations are addressed by email so people without an account can be invited
+CREATE TABLE project_invitation (
+    id BIGSERIAL PRIMARY KEY,
+    project_id BIGINT REFERENCES project(id) ON DELETE CASCADE NOT NULL,
+    inviter_id UUID REFERENCES app_user(id) ON DELETE CASCADE NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    role VARCHAR(20) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP NOT NULL,
+    accepted_at TIMESTAMP,
+    declined_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_project_invitation_project_id ON project_invitation(project_id);
+CREATE INDEX idx_project_invitation_email ON project_invitation(LOWER(email));
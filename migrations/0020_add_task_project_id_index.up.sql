@@ -0,0 +1,2 @@
+-- project listings aggregate the latest task activity per project
+CREATE INDEX idx_task_project_id ON task(project_id);
@@ -0,0 +1,12 @@
+-- History of changes to a project. actor_id is kept empty once the acting
+-- user deletes their account so the entry itself survives.
+CREATE TABLE project_event (
+    id BIGSERIAL PRIMARY KEY,
+    project_id BIGINT NOT NULL REFERENCES project(id) ON DELETE CASCADE,
+    actor_id UUID REFERENCES app_user(id) ON DELETE SET NULL,
+    type VARCHAR(50) NOT NULL,
+    details JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_project_event_project_id_created_at ON project_event(project_id, created_at DESC);
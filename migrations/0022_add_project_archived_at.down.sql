@@ -0,0 +1 @@
+ALTER TABLE project DROP COLUMN IF EXISTS archived_at;
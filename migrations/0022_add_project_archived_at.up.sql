@@ -0,0 +1,2 @@
+-- Archived projects are read-only and left out of the default project list
+ALTER TABLE project ADD COLUMN archived_at TIMESTAMP;
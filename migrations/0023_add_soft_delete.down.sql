@@ -0,0 +1,8 @@
+-- trashed rows would reappear as live ones, so drop them first
+DELETE FROM project WHERE deleted_at IS NOT NULL;
+DELETE FROM task WHERE deleted_at IS NOT NULL;
+DELETE FROM subtask WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE subtask DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE task DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE project DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,9 @@
+-- Deleted projects, tasks and subtasks stay in the trash until they are
+-- restored or purged after the retention period
+ALTER TABLE project ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE task ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE subtask ADD COLUMN deleted_at TIMESTAMP;
+
+CREATE INDEX idx_project_deleted_at ON project(deleted_at);
+CREATE INDEX idx_task_deleted_at ON task(deleted_at);
+CREATE INDEX idx_subtask_deleted_at ON subtask(deleted_at);
@@ -0,0 +1,12 @@
+-- Saved project templates. tasks holds the task list as JSON with due dates
+-- stored as a number of days after the project's start.
+CREATE TABLE project_template (
+    id BIGSERIAL PRIMARY KEY,
+    owner_id UUID NOT NULL REFERENCES app_user(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    tasks JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_project_template_owner_id ON project_template(owner_id);
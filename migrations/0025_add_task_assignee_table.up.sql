@@ -0,0 +1,10 @@
+CREATE TABLE task_assignee (
+    id BIGSERIAL PRIMARY KEY,
+    task_id BIGINT NOT NULL REFERENCES task(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES app_user(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL,
+    UNIQUE (task_id, user_id)
+);
+
+-- "assigned to me" looks tasks up by user
+CREATE INDEX idx_task_assignee_user_id ON task_assignee(user_id);
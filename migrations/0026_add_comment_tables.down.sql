@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS comment_edit;
+DROP TABLE IF EXISTS comment;
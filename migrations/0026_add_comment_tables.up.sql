@@ -0,0 +1,26 @@
+-- Comments on tasks and subtasks. task_id is set for subtask comments too so
+-- a task's discussion goes with it when the task is purged.
+CREATE TABLE comment (
+    id BIGSERIAL PRIMARY KEY,
+    task_id BIGINT NOT NULL REFERENCES task(id) ON DELETE CASCADE,
+    subtask_id BIGINT REFERENCES subtask(id) ON DELETE CASCADE,
+    parent_id BIGINT REFERENCES comment(id) ON DELETE CASCADE,
+    author_id UUID REFERENCES app_user(id) ON DELETE SET NULL,
+    body TEXT NOT NULL,
+    edited_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_comment_task_id ON comment(task_id);
+CREATE INDEX idx_comment_subtask_id ON comment(subtask_id);
+
+-- Earlier bodies of edited comments
+CREATE TABLE comment_edit (
+    id BIGSERIAL PRIMARY KEY,
+    comment_id BIGINT NOT NULL REFERENCES comment(id) ON DELETE CASCADE,
+    body TEXT NOT NULL,
+    edited_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_comment_edit_comment_id ON comment_edit(comment_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS mention;
+DROP INDEX IF EXISTS idx_app_user_handle;
+ALTER TABLE app_user DROP COLUMN IF EXISTS handle;
@@ -0,0 +1,15 @@
+ALTER TABLE app_user ADD COLUMN handle VARCHAR(30);
+CREATE UNIQUE INDEX idx_app_user_handle ON app_user(handle);
+
+-- Who a task description, subtask description or comment mentions
+CREATE TABLE mention (
+    id BIGSERIAL PRIMARY KEY,
+    task_id BIGINT NOT NULL REFERENCES task(id) ON DELETE CASCADE,
+    subtask_id BIGINT REFERENCES subtask(id) ON DELETE CASCADE,
+    comment_id BIGINT REFERENCES comment(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES app_user(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_mention_task_id ON mention(task_id);
+CREATE INDEX idx_mention_user_id ON mention(user_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS notification;
+ALTER TABLE app_user DROP COLUMN IF EXISTS notification_preferences;
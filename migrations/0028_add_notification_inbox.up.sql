@@ -0,0 +1,23 @@
+-- Per-user inbox of things that happened to the user's work
+CREATE TABLE notification (
+    id BIGSERIAL PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES app_user(id) ON DELETE CASCADE,
+    actor_id UUID REFERENCES app_user(id) ON DELETE SET NULL,
+    type VARCHAR(30) NOT NULL,
+    project_id BIGINT NOT NULL REFERENCES project(id) ON DELETE CASCADE,
+    task_id BIGINT REFERENCES task(id) ON DELETE CASCADE,
+    subtask_id BIGINT REFERENCES subtask(id) ON DELETE CASCADE,
+    comment_id BIGINT REFERENCES comment(id) ON DELETE CASCADE,
+    -- a JSON object with what the type needs, such as the old and new status
+    details JSONB NOT NULL DEFAULT '{}',
+    read_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_notification_user_id_created_at ON notification(user_id, created_at DESC);
+
+-- Per-type notification switches; a type missing from the object is on
+ALTER TABLE app_user ADD COLUMN notification_preferences JSONB NOT NULL DEFAULT '{}';
+
+-- The unread count is read on every inbox load
+CREATE INDEX idx_notification_user_id_unread ON notification(user_id) WHERE read_at IS NULL;
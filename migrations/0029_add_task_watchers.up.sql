@@ -0,0 +1,21 @@
+CREATE TABLE task_watcher (
+    id BIGSERIAL PRIMARY KEY,
+    task_id BIGINT NOT NULL REFERENCES task(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES app_user(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL,
+    UNIQUE (task_id, user_id)
+);
+
+CREATE INDEX idx_task_watcher_user_id ON task_watcher(user_id);
+
+-- Existing assignees, commenters and mentioned users watch their tasks
+INSERT INTO task_watcher (task_id, user_id, created_at)
+SELECT task_id, user_id, NOW() FROM (
+    SELECT task_id, user_id FROM task_assignee
+    UNION
+    SELECT task_id, assigned_to FROM subtask WHERE assigned_to IS NOT NULL
+    UNION
+    SELECT task_id, author_id FROM comment WHERE author_id IS NOT NULL
+    UNION
+    SELECT task_id, user_id FROM mention
+) AS existing;
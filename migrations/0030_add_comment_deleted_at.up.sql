@@ -0,0 +1,3 @@
+-- A deleted comment that still has replies stays behind as an empty
+-- placeholder so the replies keep their thread
+ALTER TABLE comment ADD COLUMN deleted_at TIMESTAMP;